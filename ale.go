@@ -6,7 +6,10 @@
 package ale
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -23,6 +26,12 @@ const (
 	ColumnTape     = "Tape"
 	ColumnSourceFile = "Source File"
 	ColumnFPS      = "FPS"
+	ColumnShootDate    = "Shoot Date"
+	ColumnCreationDate = "Creation Date"
+	ColumnModifiedDate = "Modified Date"
+	ColumnCamera       = "Camera"
+	ColumnScene        = "Scene"
+	ColumnTake         = "Take"
 )
 
 // Common ALE header keywords
@@ -43,11 +52,19 @@ const (
 	DefaultFieldDelim = "TABS"
 )
 
-// ALEFile represents the structure of an ALE file
+// ALEFile represents the structure of an ALE file. Rows are stored by
+// column position: Rows[i][j] is the value of Columns[j] in row i.
+// Columns may be assigned or renamed in place; lookups then scan them until
+// SetColumns indexes them again.
 type ALEFile struct {
 	Headers map[string]string
 	Columns []string
-	Rows    []map[string]string
+	Rows    [][]string
+
+	// index maps column names to positions in indexed, the columns last
+	// set by SetColumns
+	index   map[string]int
+	indexed []string
 }
 
 // NewALEFile creates a new empty ALE file structure
@@ -55,8 +72,122 @@ func NewALEFile() *ALEFile {
 	return &ALEFile{
 		Headers: make(map[string]string),
 		Columns: make([]string, 0),
-		Rows:    make([]map[string]string, 0),
+		Rows:    make([][]string, 0),
+	}
+}
+
+// SetColumns sets the columns and indexes them for lookups by name
+func (f *ALEFile) SetColumns(columns []string) {
+	f.Columns = columns
+	f.indexed = columns
+	f.index = make(map[string]int, len(columns))
+	for i, col := range columns {
+		if _, ok := f.index[col]; !ok {
+			f.index[col] = i
+		}
+	}
+}
+
+// ColumnIndex returns the position of a column in Columns
+func (f *ALEFile) ColumnIndex(column string) (int, bool) {
+	if f.indexCurrent() {
+		if i, ok := f.index[column]; ok && f.Columns[i] == column {
+			return i, true
+		}
+	}
+	i := slices.Index(f.Columns, column)
+	return i, i >= 0
+}
+
+// indexCurrent reports whether Columns is still the slice SetColumns
+// indexed
+func (f *ALEFile) indexCurrent() bool {
+	if f.index == nil || len(f.Columns) != len(f.indexed) {
+		return false
+	}
+	return len(f.Columns) == 0 || &f.Columns[0] == &f.indexed[0]
+}
+
+// ReadALE reads the heading, columns and rows of an ALE file without
+// converting them to clips
+func ReadALE(r io.Reader) (*ALEFile, error) {
+	return NewDecoder(r).parseALE(context.Background())
+}
+
+// WriteTo writes the file in ALE format
+func (f *ALEFile) WriteTo(w io.Writer) (int64, error) {
+	var lines []string
+
+	// Write Heading section
+	lines = append(lines, HeaderHeading)
+	for _, key := range sortedHeadingKeys(f.Headers) {
+		lines = append(lines, fmt.Sprintf("%s\t%s", key, f.Headers[key]))
 	}
+	lines = append(lines, "")
+
+	// Write Column section
+	lines = append(lines, HeaderColumn)
+	lines = append(lines, joinTabs(f.Columns))
+	lines = append(lines, "")
+
+	// Write Data section
+	lines = append(lines, HeaderData)
+	for _, row := range f.Rows {
+		lines = append(lines, joinTabs(row))
+	}
+
+	output := strings.Join(lines, "\n")
+	if !strings.HasSuffix(output, "\n") {
+		output += "\n"
+	}
+	n, err := io.WriteString(w, output)
+	return int64(n), err
+}
+
+// Row returns a view of data row i
+func (f *ALEFile) Row(i int) Row {
+	return Row{file: f, values: f.Rows[i]}
+}
+
+// AppendRow appends a row given as column name to value
+func (f *ALEFile) AppendRow(values map[string]string) {
+	row := make([]string, len(f.Columns))
+	for i, col := range f.Columns {
+		row[i] = values[col]
+	}
+	f.Rows = append(f.Rows, row)
+}
+
+// Set sets the value of a column in data row i, appending the column,
+// with an empty value in every row, if the file does not have it
+func (f *ALEFile) Set(i int, column, value string) {
+	j, ok := f.ColumnIndex(column)
+	if !ok {
+		f.SetColumns(append(slices.Clip(f.Columns), column))
+		j = len(f.Columns) - 1
+		for k, row := range f.Rows {
+			if len(row) < len(f.Columns) {
+				f.Rows[k] = append(row, make([]string, len(f.Columns)-len(row))...)
+			}
+		}
+	}
+	if len(f.Rows[i]) <= j {
+		f.Rows[i] = append(f.Rows[i], make([]string, j+1-len(f.Rows[i]))...)
+	}
+	f.Rows[i][j] = value
+}
+
+// RowMaps returns the data rows as maps from column name to value, the
+// row representation used before rows were stored by column position
+func (f *ALEFile) RowMaps() []map[string]string {
+	maps := make([]map[string]string, len(f.Rows))
+	for i := range f.Rows {
+		maps[i] = make(map[string]string, len(f.Columns))
+		for col, value := range f.Row(i).All() {
+			maps[i][col] = value
+		}
+	}
+	return maps
 }
 
 // parseTimecode attempts to parse a timecode string and return a RationalTime
@@ -143,10 +274,16 @@ func isDropFrame(fps float64) bool {
 	return (fps > 29.96 && fps < 29.98) || (fps > 59.93 && fps < 59.95)
 }
 
-// CDLData represents ASC CDL color correction data
+// CDLData represents an ASC CDL v1.2 color correction: its id and
+// descriptions, slope/offset/power and saturation. A nil ASCSOP or ASCSat
+// is the identity.
 type CDLData struct {
-	ASCSOP *SOPValues `json:"asc_sop,omitempty"`
-	ASCSat *float64   `json:"asc_sat,omitempty"`
+	ID                 string     `json:"id,omitempty"`
+	Descriptions       []string   `json:"descriptions,omitempty"`
+	InputDescription   string     `json:"input_description,omitempty"`
+	ViewingDescription string     `json:"viewing_description,omitempty"`
+	ASCSOP             *SOPValues `json:"asc_sop,omitempty"`
+	ASCSat             *float64   `json:"asc_sat,omitempty"`
 }
 
 // SOPValues represents Slope, Offset, Power values for CDL
@@ -184,6 +321,107 @@ func parseASCCDL(ascSOP, ascSat string) (*CDLData, error) {
 	return nil, nil
 }
 
+// cdlFromMap reads CDL data stored as a map with the CDLData JSON keys, as
+// it is after a JSON round trip. It returns nil if the map holds no CDL.
+func cdlFromMap(m map[string]interface{}) *CDLData {
+	cdl := &CDLData{}
+
+	switch sop := m["asc_sop"].(type) {
+	case *SOPValues:
+		cdl.ASCSOP = sop
+	case SOPValues:
+		cdl.ASCSOP = &sop
+	case string:
+		if values, err := parseASCSOP(sop); err == nil {
+			cdl.ASCSOP = values
+		}
+	default:
+		if sopMap := asMap(sop); sopMap != nil {
+			values := &SOPValues{}
+			slope, okSlope := floatTriple(sopMap["slope"])
+			offset, okOffset := floatTriple(sopMap["offset"])
+			power, okPower := floatTriple(sopMap["power"])
+			if okSlope && okOffset && okPower {
+				values.Slope, values.Offset, values.Power = slope, offset, power
+				cdl.ASCSOP = values
+			}
+		}
+	}
+
+	if sat, ok := floatValue(m["asc_sat"]); ok {
+		cdl.ASCSat = &sat
+	}
+
+	cdl.ID = stringValue(m["id"])
+	cdl.InputDescription = stringValue(m["input_description"])
+	cdl.ViewingDescription = stringValue(m["viewing_description"])
+	switch descriptions := m["descriptions"].(type) {
+	case []string:
+		cdl.Descriptions = descriptions
+	case []interface{}:
+		for _, description := range descriptions {
+			if s, ok := description.(string); ok {
+				cdl.Descriptions = append(cdl.Descriptions, s)
+			}
+		}
+	}
+
+	if cdl.isZero() {
+		return nil
+	}
+	return cdl
+}
+
+// floatTriple reads three numbers from an array or slice
+func floatTriple(value interface{}) ([3]float64, bool) {
+	var triple [3]float64
+	switch v := value.(type) {
+	case [3]float64:
+		return v, true
+	case []float64:
+		if len(v) != 3 {
+			return triple, false
+		}
+		copy(triple[:], v)
+		return triple, true
+	case []interface{}:
+		if len(v) != 3 {
+			return triple, false
+		}
+		for i, item := range v {
+			f, ok := floatValue(item)
+			if !ok {
+				return triple, false
+			}
+			triple[i] = f
+		}
+		return triple, true
+	}
+	return triple, false
+}
+
+// floatValue reads a number stored as a float, integer or string
+func floatValue(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case *float64:
+		if v != nil {
+			return *v, true
+		}
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
 // parseASCSOP parses an ASC_SOP string like "(0.8714 0.9334 0.9947)(-0.087 -0.0922 -0.0808)(0.9988 1.0218 1.0101)"
 func parseASCSOP(s string) (*SOPValues, error) {
 	// Remove all parentheses and split by whitespace
@@ -227,12 +465,40 @@ func parseASCSOP(s string) (*SOPValues, error) {
 	return sop, nil
 }
 
+// DefaultCDLPrecision writes CDL values with four decimals, or more when
+// four would change the value
+const DefaultCDLPrecision = -1
+
+// cdlDecimals is the number of decimals CDL values are usually written with
+const cdlDecimals = 4
+
+// formatCDLValue formats a slope, offset, power or saturation value with
+// a fixed number of decimals, or losslessly if precision is negative
+func formatCDLValue(v float64, precision int) string {
+	if precision >= 0 {
+		return strconv.FormatFloat(v, 'f', precision, 64)
+	}
+	fixed := strconv.FormatFloat(v, 'f', cdlDecimals, 64)
+	if parsed, err := strconv.ParseFloat(fixed, 64); err == nil && parsed == v {
+		return fixed
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
 // formatASCSOP formats SOPValues back to ASC_SOP string format
-func formatASCSOP(sop *SOPValues) string {
-	return fmt.Sprintf("(%.4f %.4f %.4f)(%.4f %.4f %.4f)(%.4f %.4f %.4f)",
-		sop.Slope[0], sop.Slope[1], sop.Slope[2],
-		sop.Offset[0], sop.Offset[1], sop.Offset[2],
-		sop.Power[0], sop.Power[1], sop.Power[2])
+func formatASCSOP(sop *SOPValues, precision int) string {
+	var b strings.Builder
+	for _, group := range [][3]float64{sop.Slope, sop.Offset, sop.Power} {
+		b.WriteByte('(')
+		for i, v := range group {
+			if i > 0 {
+				b.WriteByte(' ')
+			}
+			b.WriteString(formatCDLValue(v, precision))
+		}
+		b.WriteByte(')')
+	}
+	return b.String()
 }
 
 // videoFormatFromDimensions infers Avid video format from width and height
@@ -256,11 +522,13 @@ func videoFormatFromDimensions(width, height int) string {
 	}
 }
 
+// imageSizeReplacer splits an image size on its "x"
+var imageSizeReplacer = strings.NewReplacer(" ", "", "x", " ", "X", " ")
+
 // parseImageSize extracts width and height from "Image Size" metadata field
 func parseImageSize(imageSize string) (width, height int, ok bool) {
 	// Match patterns like "1920 x 1080" or "1920x1080"
-	re := strings.NewReplacer(" ", "", "x", " ", "X", " ")
-	normalized := re.Replace(imageSize)
+	normalized := imageSizeReplacer.Replace(imageSize)
 
 	var w, h int
 	n, _ := fmt.Sscanf(normalized, "%d %d", &w, &h)
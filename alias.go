@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package ale
+
+import (
+	"strings"
+	"unicode"
+)
+
+// DefaultColumnAliases maps vendor column names to the canonical Avid
+// column names used for clip names, timecodes and media references
+var DefaultColumnAliases = map[string]string{
+	"Clip Name":      ColumnName,
+	"Clipname":       ColumnName,
+	"Filename":       ColumnSourceFile,
+	"File Name":      ColumnSourceFile,
+	"Reel":           ColumnTape,
+	"Reel #":         ColumnTape,
+	"Reel Name":      ColumnTape,
+	"Camroll":        ColumnTape,
+	"Original_Start": ColumnStart,
+	"Start TC":       ColumnStart,
+	"Original_End":   ColumnEnd,
+	"End TC":         ColumnEnd,
+	"Track":          ColumnTracks,
+}
+
+// roleColumns lists the canonical columns mapped to clip properties
+var roleColumns = []string{
+	ColumnName,
+	ColumnTracks,
+	ColumnStart,
+	ColumnEnd,
+	ColumnDuration,
+	ColumnTape,
+	ColumnSourceFile,
+}
+
+// columnAliases maps normalized column names to canonical column names
+type columnAliases map[string]string
+
+// newColumnAliases builds an alias table from the defaults, the canonical
+// names themselves and any extra aliases, which take precedence
+func newColumnAliases(extra map[string]string) columnAliases {
+	aliases := make(columnAliases)
+	for _, col := range roleColumns {
+		aliases[normalizeColumnName(col)] = col
+	}
+	for alias, canonical := range DefaultColumnAliases {
+		aliases[normalizeColumnName(alias)] = canonical
+	}
+	for alias, canonical := range extra {
+		aliases[normalizeColumnName(alias)] = canonical
+	}
+	return aliases
+}
+
+// canonical returns the canonical name for a column, or "" if the column
+// is neither a canonical column nor a known alias
+func (a columnAliases) canonical(column string) string {
+	return a[normalizeColumnName(column)]
+}
+
+// isAlias reports whether a column is an alias rather than the exact
+// canonical name
+func (a columnAliases) isAlias(column string) bool {
+	canonical := a.canonical(column)
+	return canonical != "" && canonical != column
+}
+
+// resolve picks the file column used for each canonical column. Exact
+// (case- and whitespace-insensitive) canonical matches win over aliases;
+// otherwise the first alias in column order is used.
+func (a columnAliases) resolve(columns []string) map[string]string {
+	resolved := make(map[string]string)
+
+	for _, col := range columns {
+		canonical := a.canonical(col)
+		if canonical == "" || normalizeColumnName(col) != normalizeColumnName(canonical) {
+			continue
+		}
+		if _, ok := resolved[canonical]; !ok {
+			resolved[canonical] = col
+		}
+	}
+
+	for _, col := range columns {
+		canonical := a.canonical(col)
+		if canonical == "" {
+			continue
+		}
+		if _, ok := resolved[canonical]; !ok {
+			resolved[canonical] = col
+		}
+	}
+
+	return resolved
+}
+
+// normalizeColumnName lowercases a column name and drops whitespace and
+// underscores, so "Clip Name", "clipname" and "CLIP_NAME" compare equal
+func normalizeColumnName(column string) string {
+	var b strings.Builder
+	for _, r := range column {
+		if unicode.IsSpace(r) || r == '_' {
+			continue
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
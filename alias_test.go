@@ -0,0 +1,239 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package ale
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Avalanche-io/gotio"
+)
+
+func TestColumnAliases_Canonical(t *testing.T) {
+	aliases := newColumnAliases(map[string]string{"Roll": ColumnTape})
+
+	tests := []struct {
+		column string
+		want   string
+	}{
+		{"Name", ColumnName},
+		{"NAME", ColumnName},
+		{"Clip Name", ColumnName},
+		{"clipname", ColumnName},
+		{" Reel # ", ColumnTape},
+		{"Camroll", ColumnTape},
+		{"Roll", ColumnTape},
+		{"original start", ColumnStart},
+		{"Source File", ColumnSourceFile},
+		{"Scene", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.column, func(t *testing.T) {
+			if got := aliases.canonical(tt.column); got != tt.want {
+				t.Errorf("canonical(%q) = %q, want %q", tt.column, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestColumnAliases_Resolve(t *testing.T) {
+	aliases := newColumnAliases(nil)
+
+	// Exact canonical names win over aliases that appear earlier
+	resolved := aliases.resolve([]string{"Clipname", "Name", "Reel", "Camroll", "Original_Start"})
+
+	if resolved[ColumnName] != "Name" {
+		t.Errorf("Name resolved to %q, want Name", resolved[ColumnName])
+	}
+	if resolved[ColumnTape] != "Reel" {
+		t.Errorf("Tape resolved to %q, want Reel", resolved[ColumnTape])
+	}
+	if resolved[ColumnStart] != "Original_Start" {
+		t.Errorf("Start resolved to %q, want Original_Start", resolved[ColumnStart])
+	}
+	if _, ok := resolved[ColumnEnd]; ok {
+		t.Errorf("End resolved to %q, want unresolved", resolved[ColumnEnd])
+	}
+}
+
+func TestDecoder_WithVendorColumnNames(t *testing.T) {
+	aleContent := `Heading
+FIELD_DELIM	TABS
+FPS	24
+
+Column
+Clip Name	Reel #	Original_Start	Original_End	Scene
+
+Data
+Clip001	A001	01:00:00:00	01:00:05:00	Scene1
+`
+
+	timeline, err := NewDecoder(strings.NewReader(aleContent)).Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode ALE: %v", err)
+	}
+
+	clip := timeline.FindClips(nil, false)[0]
+	if clip.Name() != "Clip001" {
+		t.Errorf("Expected clip name 'Clip001', got '%s'", clip.Name())
+	}
+
+	if clip.SourceRange() == nil || clip.SourceRange().Duration().Value() != 120 {
+		t.Errorf("Expected 120 frame source range, got %v", clip.SourceRange())
+	}
+
+	extRef, ok := clip.MediaReference().(*gotio.ExternalReference)
+	if !ok || extRef.TargetURL() != "A001" {
+		t.Errorf("Expected media reference from Reel # column, got %v", clip.MediaReference())
+	}
+
+	aleMap := clip.Metadata()["ALE"].(map[string]interface{})
+	if _, ok := aleMap["Clip Name"]; ok {
+		t.Error("Clip Name should be mapped to the clip name, not ALE metadata")
+	}
+	if aleMap["Scene"] != "Scene1" {
+		t.Errorf("Expected Scene 'Scene1', got '%v'", aleMap["Scene"])
+	}
+
+	// Vendor columns are written back under their own names
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).Encode(timeline); err != nil {
+		t.Fatalf("Failed to encode timeline: %v", err)
+	}
+	aleFile, err := ReadALE(&buf)
+	if err != nil {
+		t.Fatalf("Failed to read encoded ALE: %v", err)
+	}
+	want := map[string]string{"Clip Name": "Clip001", "Reel #": "A001", "Original_Start": "01:00:00:00", "Original_End": "01:00:05:00"}
+	for col, value := range want {
+		if got := aleFile.Row(0).Get(col); got != value {
+			t.Errorf("%s = %q, want %q (columns %v)", col, got, value, aleFile.Columns)
+		}
+	}
+}
+
+func TestDecoder_WithColumnAliases(t *testing.T) {
+	aleContent := `Heading
+FIELD_DELIM	TABS
+
+Column
+Shot	Duration
+
+Data
+Shot001	50
+`
+
+	decoder := NewDecoder(strings.NewReader(aleContent), WithColumnAliases(map[string]string{"shot": ColumnName}))
+	timeline, err := decoder.Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode ALE: %v", err)
+	}
+
+	if name := timeline.FindClips(nil, false)[0].Name(); name != "Shot001" {
+		t.Errorf("Expected clip name 'Shot001', got '%s'", name)
+	}
+}
+
+func TestDecoder_Sample2FilenameAlias(t *testing.T) {
+	data, err := os.ReadFile("testdata/sample2.ale")
+	if err != nil {
+		t.Fatalf("Failed to read test file: %v", err)
+	}
+
+	timeline, err := NewDecoder(bytes.NewReader(data)).Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode ALE: %v", err)
+	}
+
+	clip := timeline.FindClips(nil, false)[0]
+	extRef, ok := clip.MediaReference().(*gotio.ExternalReference)
+	if !ok || extRef.TargetURL() != "A007C001_140720_R2E4" {
+		t.Errorf("Expected media reference from Filename column, got %v", clip.MediaReference())
+	}
+
+	// Clipname is an alias, but Name is present so it stays in metadata
+	aleMap := clip.Metadata()["ALE"].(map[string]interface{})
+	if aleMap["Clipname"] != "A007C001" {
+		t.Errorf("Expected Clipname 'A007C001', got '%v'", aleMap["Clipname"])
+	}
+	// Tape did not fill the media reference, so it is kept too
+	if aleMap[ColumnTape] != "3NY004" {
+		t.Errorf("Expected Tape '3NY004', got '%v'", aleMap[ColumnTape])
+	}
+
+	// The media reference is written back under its logged column name
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).Encode(timeline); err != nil {
+		t.Fatalf("Failed to encode timeline: %v", err)
+	}
+	aleFile, err := ReadALE(&buf)
+	if err != nil {
+		t.Fatalf("Failed to read encoded ALE: %v", err)
+	}
+	if _, ok := aleFile.ColumnIndex(ColumnSourceFile); ok {
+		t.Errorf("Columns = %v, want Filename kept without WithCanonicalColumns", aleFile.Columns)
+	}
+	row := aleFile.Row(0)
+	if row.Get("Filename") != "A007C001_140720_R2E4" || row.Get(ColumnTape) != "3NY004" {
+		t.Errorf("Filename, Tape = %q, %q", row.Get("Filename"), row.Get(ColumnTape))
+	}
+
+	buf.Reset()
+	if err := NewEncoder(&buf, WithCanonicalColumns(true)).Encode(timeline); err != nil {
+		t.Fatalf("Failed to encode timeline: %v", err)
+	}
+	if aleFile, err = ReadALE(&buf); err != nil {
+		t.Fatalf("Failed to read encoded ALE: %v", err)
+	}
+	if got := aleFile.Row(0).Get(ColumnSourceFile); got != "A007C001_140720_R2E4" {
+		t.Errorf("Source File with WithCanonicalColumns = %q", got)
+	}
+}
+
+func TestEncoder_WithCanonicalColumns(t *testing.T) {
+	aleContent := `Heading
+FIELD_DELIM	TABS
+
+Column
+Name	Duration	Reel
+
+Data
+Clip001	100	R001
+`
+
+	timeline, err := NewDecoder(strings.NewReader(aleContent), WithColumnAliases(map[string]string{"Reel": ""})).Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode ALE: %v", err)
+	}
+
+	columns := []string{"Clip Name", "Duration", "Reel"}
+
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf, WithColumns(columns)).Encode(timeline); err != nil {
+		t.Fatalf("Failed to encode timeline: %v", err)
+	}
+	if !strings.Contains(buf.String(), "Clip Name\tDuration\tReel\n") {
+		t.Errorf("Expected alias column names by default:\n%s", buf.String())
+	}
+	if !strings.Contains(buf.String(), "Clip001\t100\tR001") {
+		t.Errorf("Expected Clip Name filled from the clip name:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := NewEncoder(&buf, WithColumns(columns), WithCanonicalColumns(true)).Encode(timeline); err != nil {
+		t.Fatalf("Failed to encode timeline: %v", err)
+	}
+	if !strings.Contains(buf.String(), "Name\tDuration\tTape\n") {
+		t.Errorf("Expected canonical column names:\n%s", buf.String())
+	}
+	if !strings.Contains(buf.String(), "Clip001\t100\tR001") {
+		t.Errorf("Expected values under canonical columns:\n%s", buf.String())
+	}
+	if columns[0] != "Clip Name" {
+		t.Error("WithColumns slice was modified")
+	}
+}
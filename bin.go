@@ -0,0 +1,125 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package ale
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Avalanche-io/gotio"
+)
+
+// DefaultBinName is the name of a bin returned by DecodeBin
+const DefaultBinName = "ALE Bin"
+
+// DecodeBin parses an ALE file and returns its clips as a bin of master
+// clips rather than an edited sequence. With WithBinGrouping, clips are
+// nested in one SerializableCollection per group key.
+func (d *Decoder) DecodeBin() (*gotio.SerializableCollection, error) {
+	return d.DecodeBinContext(context.Background())
+}
+
+// DecodeBinContext is like DecodeBin but stops once ctx is done
+func (d *Decoder) DecodeBinContext(ctx context.Context) (*gotio.SerializableCollection, error) {
+	aleFile, err := d.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.aleToBin(ctx, aleFile)
+}
+
+// aleToBin converts an ALEFile structure to a SerializableCollection
+func (d *Decoder) aleToBin(ctx context.Context, aleFile *ALEFile) (*gotio.SerializableCollection, error) {
+	clips, rows, err := d.decodeRows(ctx, aleFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.binGrouping.Key == nil {
+		children := make([]gotio.SerializableObject, len(clips))
+		for i, clip := range clips {
+			children[i] = d.item(clip)
+		}
+		return gotio.NewSerializableCollection(d.timelineName(DefaultBinName), children, d.headingMetadata(aleFile)), nil
+	}
+
+	// Nest clips by group key
+	groups := make(map[string][]gotio.SerializableObject)
+	kinds := make(map[string]string)
+	var keys []string
+	for i, clip := range clips {
+		key, kind := d.binGrouping.Key(rows[i])
+		if key == "" {
+			key = kind
+		}
+		if _, exists := groups[key]; !exists {
+			kinds[key] = kind
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], d.item(clip))
+	}
+
+	d.binGrouping.sort(keys, kinds)
+
+	children := make([]gotio.SerializableObject, len(keys))
+	for i, key := range keys {
+		children[i] = gotio.NewSerializableCollection(key, groups[key], nil)
+	}
+	return gotio.NewSerializableCollection(d.timelineName(DefaultBinName), children, d.headingMetadata(aleFile)), nil
+}
+
+// EncodeCollection writes the clips of a bin-style SerializableCollection
+// as an ALE file. Nested collections, timelines and compositions are
+// searched for clips in order.
+func (e *Encoder) EncodeCollection(collection *gotio.SerializableCollection) error {
+	return e.EncodeCollectionContext(context.Background(), collection)
+}
+
+// EncodeCollectionContext is like EncodeCollection but stops once ctx is
+// done, without writing anything
+func (e *Encoder) EncodeCollectionContext(ctx context.Context, collection *gotio.SerializableCollection) error {
+	if collection == nil {
+		return fmt.Errorf("collection cannot be nil")
+	}
+
+	aleFile, err := e.clipsToALE(ctx, collectionClips(collection), true, e.headingValues(collection.Metadata()))
+	if err != nil {
+		return fmt.Errorf("failed to convert collection to ALE: %w", err)
+	}
+
+	return e.writeALE(ctx, aleFile)
+}
+
+// collectionClips returns the clips in a collection, depth first
+func collectionClips(collection *gotio.SerializableCollection) []*gotio.Clip {
+	var clips []*gotio.Clip
+	for _, child := range collection.Children() {
+		switch item := child.(type) {
+		case *gotio.Clip:
+			clips = append(clips, item)
+		case *gotio.SerializableCollection:
+			clips = append(clips, collectionClips(item)...)
+		case *gotio.Timeline:
+			clips = append(clips, item.FindClips(nil, false)...)
+		case gotio.Composition:
+			clips = append(clips, compositionClips(item)...)
+		}
+	}
+	return clips
+}
+
+// compositionClips returns the clips in a composition, depth first
+func compositionClips(composition gotio.Composition) []*gotio.Clip {
+	var clips []*gotio.Clip
+	for _, child := range composition.Children() {
+		switch item := child.(type) {
+		case *gotio.Clip:
+			clips = append(clips, item)
+		case gotio.Composition:
+			clips = append(clips, compositionClips(item)...)
+		}
+	}
+	return clips
+}
@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package ale
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Avalanche-io/gotio"
+)
+
+const binALE = `Heading
+FIELD_DELIM	TABS
+FPS	24
+
+Column
+Name	Camroll	Start	End
+
+Data
+B002	B001	01:00:00:00	01:00:10:00
+A001	A001	02:00:00:00	02:00:10:00
+A002	A001	02:00:20:00	02:00:30:00
+`
+
+func TestDecoder_DecodeBin(t *testing.T) {
+	bin, err := NewDecoder(strings.NewReader(binALE)).DecodeBin()
+	if err != nil {
+		t.Fatalf("Failed to decode ALE: %v", err)
+	}
+
+	if bin.Name() != DefaultBinName {
+		t.Errorf("Bin name = %q, want %q", bin.Name(), DefaultBinName)
+	}
+
+	// Clips stay in row order
+	var names []string
+	for _, child := range bin.Children() {
+		clip, ok := child.(*gotio.Clip)
+		if !ok {
+			t.Fatalf("Bin child is %T, want *gotio.Clip", child)
+		}
+		names = append(names, clip.Name())
+	}
+	if got := strings.Join(names, ","); got != "B002,A001,A002" {
+		t.Errorf("Bin clips = %s, want B002,A001,A002", got)
+	}
+
+	// The encoder writes the bin back without a sequence
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).EncodeCollection(bin); err != nil {
+		t.Fatalf("Failed to encode collection: %v", err)
+	}
+
+	roundTrip, err := NewDecoder(bytes.NewReader(buf.Bytes())).DecodeBin()
+	if err != nil {
+		t.Fatalf("Failed to decode encoded bin: %v", err)
+	}
+	if len(roundTrip.Children()) != 3 {
+		t.Fatalf("Expected 3 clips after round trip, got %d", len(roundTrip.Children()))
+	}
+	clip := roundTrip.Children()[2].(*gotio.Clip)
+	if tc, _ := formatTimecode(clip.SourceRange().StartTime(), 24, false); tc != "02:00:20:00" {
+		t.Errorf("Round trip start = %s, want 02:00:20:00", tc)
+	}
+}
+
+func TestDecoder_WithBinGrouping(t *testing.T) {
+	decoder := NewDecoder(strings.NewReader(binALE), WithBinGrouping(GroupByColumn("Camroll")))
+	bin, err := decoder.DecodeBin()
+	if err != nil {
+		t.Fatalf("Failed to decode ALE: %v", err)
+	}
+
+	want := map[string]int{"Camroll A001": 2, "Camroll B001": 1}
+	children := bin.Children()
+	if len(children) != len(want) {
+		t.Fatalf("Expected %d groups, got %d", len(want), len(children))
+	}
+	for i, name := range []string{"Camroll A001", "Camroll B001"} {
+		group, ok := children[i].(*gotio.SerializableCollection)
+		if !ok {
+			t.Fatalf("Group %d is %T, want *gotio.SerializableCollection", i, children[i])
+		}
+		if group.Name() != name {
+			t.Errorf("Group %d name = %q, want %q", i, group.Name(), name)
+		}
+		if len(group.Children()) != want[name] {
+			t.Errorf("Group %q has %d clips, want %d", name, len(group.Children()), want[name])
+		}
+	}
+
+	// Nested collections are flattened when encoding
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).EncodeCollection(bin); err != nil {
+		t.Fatalf("Failed to encode collection: %v", err)
+	}
+	for _, name := range []string{"A001", "A002", "B002"} {
+		if !strings.Contains(buf.String(), name) {
+			t.Errorf("Output missing clip %q", name)
+		}
+	}
+}
+
+func TestEncoder_EncodeCollectionNil(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).EncodeCollection(nil); err == nil {
+		t.Error("Expected error for nil collection")
+	}
+}
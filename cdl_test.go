@@ -4,9 +4,17 @@
 package ale
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"math/rand/v2"
 	"os"
 	"strings"
 	"testing"
+
+	"github.com/Avalanche-io/gotio"
+	"github.com/Avalanche-io/gotio/opentime"
 )
 
 func TestParseASCSOP(t *testing.T) {
@@ -77,7 +85,7 @@ func TestFormatASCSOP(t *testing.T) {
 		Power:  [3]float64{0.9988, 1.0218, 1.0101},
 	}
 
-	result := formatASCSOP(sop)
+	result := formatASCSOP(sop, DefaultCDLPrecision)
 
 	// Should contain all values in parentheses
 	if !strings.Contains(result, "0.8714") {
@@ -181,3 +189,617 @@ func TestParseImageSize(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatCDLValue(t *testing.T) {
+	tests := []struct {
+		value     float64
+		precision int
+		want      string
+	}{
+		{0.85, DefaultCDLPrecision, "0.8500"},
+		{1, DefaultCDLPrecision, "1.0000"},
+		{-0.0922, DefaultCDLPrecision, "-0.0922"},
+		{0.123456789, DefaultCDLPrecision, "0.123456789"},
+		{1.0 / 3.0, DefaultCDLPrecision, "0.3333333333333333"},
+		{0.85, 2, "0.85"},
+		{0.123456789, 4, "0.1235"},
+	}
+	for _, tt := range tests {
+		if got := formatCDLValue(tt.value, tt.precision); got != tt.want {
+			t.Errorf("formatCDLValue(%v, %d) = %s, want %s", tt.value, tt.precision, got, tt.want)
+		}
+	}
+}
+
+// cdlTimeline returns a timeline with one clip per CDL
+func cdlTimeline(cdls []*CDLData) *gotio.Timeline {
+	timeline := gotio.NewTimeline("CDL Timeline", nil, nil)
+	track := gotio.NewTrack("Video", nil, gotio.TrackKindVideo, nil, nil)
+	for i, cdl := range cdls {
+		sourceRange := opentime.NewTimeRange(
+			opentime.NewRationalTime(float64(i*24), 24),
+			opentime.NewRationalTime(24, 24),
+		)
+		clip := gotio.NewClip("Clip", nil, &sourceRange, gotio.AnyDictionary{DefaultCDLMetadataKey: cdl}, nil, nil, "", nil)
+		track.AppendChild(clip)
+	}
+	timeline.Tracks().AppendChild(track)
+	return timeline
+}
+
+func TestCDLRoundTrip_BitIdentical(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	value := func() float64 {
+		// Mix grading-style values with arbitrary doubles
+		if rng.IntN(2) == 0 {
+			return float64(rng.IntN(40000)-20000) / 10000
+		}
+		return rng.Float64()*4 - 2
+	}
+
+	cdls := make([]*CDLData, 200)
+	for i := range cdls {
+		sop := &SOPValues{}
+		for c := 0; c < 3; c++ {
+			sop.Slope[c] = value()
+			sop.Offset[c] = value()
+			sop.Power[c] = value()
+		}
+		sat := value()
+		cdls[i] = &CDLData{ASCSOP: sop, ASCSat: &sat}
+	}
+	sat := 0.85
+	cdls = append(cdls, &CDLData{ASCSOP: &SOPValues{Slope: [3]float64{1, 1, 1}, Power: [3]float64{1, 1, 1}}, ASCSat: &sat})
+
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).Encode(cdlTimeline(cdls)); err != nil {
+		t.Fatalf("Failed to encode timeline: %v", err)
+	}
+	timeline, err := NewDecoder(bytes.NewReader(buf.Bytes())).Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode ALE: %v", err)
+	}
+
+	clips := timeline.FindClips(nil, false)
+	if len(clips) != len(cdls) {
+		t.Fatalf("Expected %d clips, got %d", len(cdls), len(clips))
+	}
+	for i, clip := range clips {
+		got, ok := clip.Metadata()[DefaultCDLMetadataKey].(*CDLData)
+		if !ok || got.ASCSOP == nil || got.ASCSat == nil {
+			t.Fatalf("Clip %d lost its CDL", i)
+		}
+		if *got.ASCSOP != *cdls[i].ASCSOP {
+			t.Errorf("Clip %d SOP = %+v, want %+v", i, *got.ASCSOP, *cdls[i].ASCSOP)
+		}
+		if *got.ASCSat != *cdls[i].ASCSat {
+			t.Errorf("Clip %d SAT = %v, want %v", i, *got.ASCSat, *cdls[i].ASCSat)
+		}
+	}
+
+	// The usual four-decimal spelling is kept where it is exact
+	if !strings.Contains(buf.String(), "0.8500\t(1.0000 1.0000 1.0000)(0.0000 0.0000 0.0000)(1.0000 1.0000 1.0000)") {
+		t.Error("Output missing four-decimal CDL for an exact grade")
+	}
+}
+
+func TestEncoder_WithCDLPrecision(t *testing.T) {
+	sat := 0.85
+	sop := &SOPValues{Slope: [3]float64{1.23456, 1, 1}, Power: [3]float64{1, 1, 1}}
+
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf, WithCDLPrecision(2)).Encode(cdlTimeline([]*CDLData{{ASCSOP: sop, ASCSat: &sat}})); err != nil {
+		t.Fatalf("Failed to encode timeline: %v", err)
+	}
+	if !strings.Contains(buf.String(), "0.85\t(1.23 1.00 1.00)(0.00 0.00 0.00)(1.00 1.00 1.00)") {
+		t.Errorf("Output missing two-decimal CDL:\n%s", buf.String())
+	}
+}
+
+func TestEncoder_CDLMap(t *testing.T) {
+	data, err := os.ReadFile("testdata/sample_cdl.ale")
+	if err != nil {
+		t.Fatalf("Failed to read test file: %v", err)
+	}
+	timeline, err := NewDecoder(bytes.NewReader(data)).Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode ALE: %v", err)
+	}
+
+	var want bytes.Buffer
+	if err := NewEncoder(&want).Encode(timeline); err != nil {
+		t.Fatalf("Failed to encode timeline: %v", err)
+	}
+
+	// Replace each clip's CDL with its JSON round trip, as reading a .otio
+	// file back leaves it
+	for _, clip := range timeline.FindClips(nil, false) {
+		encoded, err := json.Marshal(clip.Metadata()[DefaultCDLMetadataKey])
+		if err != nil {
+			t.Fatalf("Failed to marshal CDL: %v", err)
+		}
+		var cdl map[string]interface{}
+		if err := json.Unmarshal(encoded, &cdl); err != nil {
+			t.Fatalf("Failed to unmarshal CDL: %v", err)
+		}
+		clip.Metadata()[DefaultCDLMetadataKey] = gotio.AnyDictionary(cdl)
+	}
+
+	var got bytes.Buffer
+	if err := NewEncoder(&got).Encode(timeline); err != nil {
+		t.Fatalf("Failed to encode timeline: %v", err)
+	}
+	if got.String() != want.String() {
+		t.Errorf("Encoding with map CDL differs:\n%s\nwant:\n%s", got.String(), want.String())
+	}
+	if !strings.Contains(got.String(), "ASC_SOP") {
+		t.Error("Output missing ASC_SOP column")
+	}
+}
+
+func TestCDLFromMap(t *testing.T) {
+	tests := []struct {
+		name  string
+		input map[string]interface{}
+		sop   bool
+		sat   float64
+	}{
+		{"string forms", map[string]interface{}{"asc_sop": "(1 1 1)(0 0 0)(1 1 1)", "asc_sat": "0.85"}, true, 0.85},
+		{"float slices", map[string]interface{}{
+			"asc_sop": map[string]interface{}{"slope": []float64{1, 1, 1}, "offset": []float64{0, 0, 0}, "power": []float64{1, 1, 1}},
+			"asc_sat": 1,
+		}, true, 1},
+		{"saturation only", map[string]interface{}{"asc_sat": 0.5}, false, 0.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cdl := cdlFromMap(tt.input)
+			if cdl == nil {
+				t.Fatal("cdlFromMap() = nil")
+			}
+			if (cdl.ASCSOP != nil) != tt.sop {
+				t.Errorf("ASCSOP = %v, want set %v", cdl.ASCSOP, tt.sop)
+			}
+			if cdl.ASCSat == nil || *cdl.ASCSat != tt.sat {
+				t.Errorf("ASCSat = %v, want %v", cdl.ASCSat, tt.sat)
+			}
+		})
+	}
+
+	// A short slope is not a CDL
+	if cdl := cdlFromMap(map[string]interface{}{"asc_sop": map[string]interface{}{"slope": []interface{}{1.0}}}); cdl != nil {
+		t.Errorf("cdlFromMap() = %+v, want nil", cdl)
+	}
+}
+
+func TestCDLData_Validate(t *testing.T) {
+	sat := 0.9
+	valid := &CDLData{ID: "A001", ASCSOP: &SOPValues{Slope: [3]float64{1.2, 1, 0.8}, Offset: [3]float64{-0.1, 0, 0.1}, Power: [3]float64{1, 1.1, 0.9}}, ASCSat: &sat}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+
+	negative := -0.5
+	invalid := &CDLData{ID: "A002", ASCSOP: &SOPValues{Slope: [3]float64{-1, 1, 1}, Offset: [3]float64{math.NaN(), 0, 0}, Power: [3]float64{1, 0, 1}}, ASCSat: &negative}
+	err := invalid.Validate()
+	if err == nil {
+		t.Fatal("Validate() = nil, want an error")
+	}
+	for _, want := range []string{"A002", "red slope", "red offset", "green power", "saturation"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Validate() = %v, missing %q", err, want)
+		}
+	}
+}
+
+func TestCDLData_Apply(t *testing.T) {
+	rgb := [3]float64{0.18, 0.5, 0.9}
+	if got := (&CDLData{}).Apply(rgb); got != rgb {
+		t.Errorf("Identity Apply(%v) = %v", rgb, got)
+	}
+
+	sat := 0.0
+	cdl := &CDLData{ASCSOP: &SOPValues{Slope: [3]float64{2, 1, 1}, Offset: [3]float64{0, 0.1, 0}, Power: [3]float64{1, 1, 2}}, ASCSat: &sat}
+	got := cdl.Apply(rgb)
+	// SOP gives (0.36, 0.6, 0.81); no saturation leaves its luma
+	luma := 0.2126*0.36 + 0.7152*0.6 + 0.0722*0.81
+	for i := range got {
+		if math.Abs(got[i]-luma) > 1e-12 {
+			t.Errorf("Apply(%v)[%d] = %v, want %v", rgb, i, got[i], luma)
+		}
+	}
+
+	// Values are clamped to [0, 1]
+	if got := cdl.Apply([3]float64{1, 1, 1}); got[0] > 1 {
+		t.Errorf("Apply() = %v, want clamped values", got)
+	}
+}
+
+func TestCDLData_Compose(t *testing.T) {
+	sat := 0.8
+	samples := [][3]float64{{0.1, 0.2, 0.3}, {0.18, 0.18, 0.18}, {0.3, 0.25, 0.2}}
+
+	tests := []struct {
+		name        string
+		first, next *CDLData
+	}{
+		{"linear then power",
+			&CDLData{ASCSOP: &SOPValues{Slope: [3]float64{1.1, 1, 0.9}, Offset: [3]float64{0.01, 0, -0.01}, Power: [3]float64{1, 1, 1}}},
+			&CDLData{ASCSOP: &SOPValues{Slope: [3]float64{0.9, 1, 1.1}, Offset: [3]float64{0.02, 0, 0}, Power: [3]float64{1.2, 1, 0.8}}, ASCSat: &sat}},
+		{"power then slope",
+			&CDLData{ASCSOP: &SOPValues{Slope: [3]float64{1, 1, 1}, Offset: [3]float64{0.05, 0, 0}, Power: [3]float64{0.9, 1.1, 1}}},
+			&CDLData{ASCSOP: &SOPValues{Slope: [3]float64{0.8, 1.2, 1}, Power: [3]float64{1.1, 1, 1}}}},
+		{"saturation then uniform offset",
+			&CDLData{ASCSOP: &SOPValues{Slope: [3]float64{1.2, 1, 1}, Power: [3]float64{1, 1, 1}}, ASCSat: &sat},
+			&CDLData{ASCSOP: &SOPValues{Slope: [3]float64{0.9, 0.9, 0.9}, Offset: [3]float64{0.02, 0.02, 0.02}, Power: [3]float64{1, 1, 1}}, ASCSat: &sat}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			composed, err := tt.first.Compose(tt.next)
+			if err != nil {
+				t.Fatalf("Compose() error = %v", err)
+			}
+			for _, rgb := range samples {
+				want := tt.next.Apply(tt.first.Apply(rgb))
+				got := composed.Apply(rgb)
+				for i := range got {
+					if math.Abs(got[i]-want[i]) > 1e-12 {
+						t.Errorf("Composed Apply(%v) = %v, want %v", rgb, got, want)
+						break
+					}
+				}
+			}
+		})
+	}
+
+	// A power followed by an offset has no single CDL
+	first := &CDLData{ASCSOP: &SOPValues{Slope: [3]float64{1, 1, 1}, Power: [3]float64{1.2, 1, 1}}}
+	next := &CDLData{ASCSOP: &SOPValues{Slope: [3]float64{1, 1, 1}, Offset: [3]float64{0.1, 0, 0}, Power: [3]float64{1, 1, 1}}}
+	if _, err := first.Compose(next); err == nil {
+		t.Error("Compose() of power then offset = nil error, want an error")
+	}
+
+	// Saturation does not commute with a per-channel slope
+	saturated := &CDLData{ASCSat: &sat}
+	if _, err := saturated.Compose(next); err == nil {
+		t.Error("Compose() of saturation then per-channel offset = nil error, want an error")
+	}
+}
+
+func TestDecoder_CDLID(t *testing.T) {
+	data, err := os.ReadFile("testdata/sample2.ale")
+	if err != nil {
+		t.Fatalf("Failed to read test file: %v", err)
+	}
+	timeline, err := NewDecoder(bytes.NewReader(data)).Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode ALE: %v", err)
+	}
+
+	clip := timeline.FindClips(nil, false)[0]
+	cdl, ok := clip.Metadata()[DefaultCDLMetadataKey].(*CDLData)
+	if !ok {
+		t.Fatal("Clip missing cdl metadata")
+	}
+	if cdl.ID != "X2" || cdl.ASCSOP != nil || cdl.ASCSat != nil {
+		t.Errorf("CDL = %+v, want id X2 without values", cdl)
+	}
+}
+
+func TestEncoder_CDLDescriptions(t *testing.T) {
+	sat := 1.0
+	cdl := &CDLData{
+		ID:                 "A001C003",
+		Descriptions:       []string{"Day look", "Warmer"},
+		InputDescription:   "ARRI LogC",
+		ViewingDescription: "Rec709 monitor",
+		ASCSat:             &sat,
+	}
+
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).Encode(cdlTimeline([]*CDLData{cdl})); err != nil {
+		t.Fatalf("Failed to encode timeline: %v", err)
+	}
+	timeline, err := NewDecoder(bytes.NewReader(buf.Bytes())).Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode ALE: %v", err)
+	}
+
+	got, ok := timeline.FindClips(nil, false)[0].Metadata()[DefaultCDLMetadataKey].(*CDLData)
+	if !ok {
+		t.Fatal("Clip missing cdl metadata")
+	}
+	if got.ID != cdl.ID || got.InputDescription != cdl.InputDescription || got.ViewingDescription != cdl.ViewingDescription {
+		t.Errorf("CDL = %+v, want %+v", got, cdl)
+	}
+	if strings.Join(got.Descriptions, "|") != "Day look|Warmer" {
+		t.Errorf("Descriptions = %v, want %v", got.Descriptions, cdl.Descriptions)
+	}
+}
+
+func TestDecoder_CDLVariants(t *testing.T) {
+	want := SOPValues{
+		Slope:  [3]float64{1.1, 1, 0.9},
+		Offset: [3]float64{0.01, 0, -0.02},
+		Power:  [3]float64{1, 1.2, 1},
+	}
+	tests := []struct {
+		name    string
+		columns string
+		values  string
+	}{
+		{"combined", "CDL", "(1.1 1 0.9) (0.01 0 -0.02) (1 1.2 1) (0.8)"},
+		{"channels", "ASC_SOP_R\tASC_SOP_G\tASC_SOP_B\tASC_SAT", "(1.1 0.01 1)\t(1 0 1.2)\t(0.9 -0.02 1)\t0.8"},
+		{"operations", "Slope\tOffset\tPower\tSaturation", "(1.1 1 0.9)\t(0.01 0 -0.02)\t(1 1.2 1)\t0.8"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := "Heading\nFIELD_DELIM\tTABS\nFPS\t24\n\nColumn\nName\tStart\tEnd\t" + tt.columns +
+				"\n\nData\nA001\t01:00:00:00\t01:00:01:00\t" + tt.values + "\n"
+			decoder := NewDecoder(strings.NewReader(input))
+			timeline, err := decoder.Decode()
+			if err != nil {
+				t.Fatalf("Failed to decode ALE: %v", err)
+			}
+			cdl, ok := timeline.FindClips(nil, false)[0].Metadata()[DefaultCDLMetadataKey].(*CDLData)
+			if !ok || cdl.ASCSOP == nil || cdl.ASCSat == nil {
+				t.Fatalf("CDL = %+v, want a full grade", cdl)
+			}
+			if *cdl.ASCSOP != want || *cdl.ASCSat != 0.8 {
+				t.Errorf("CDL = %+v sat %v, want %+v sat 0.8", *cdl.ASCSOP, *cdl.ASCSat, want)
+			}
+			if warnings := decoder.Warnings(); len(warnings) != 0 {
+				t.Errorf("Warnings = %v, want none", warnings)
+			}
+		})
+	}
+}
+
+func TestDecoder_CDLConflict(t *testing.T) {
+	input := "Heading\nFIELD_DELIM\tTABS\nFPS\t24\n\nColumn\nName\tStart\tEnd\tASC_SOP\tASC_SAT\tCDL\n\nData\n" +
+		"A001\t01:00:00:00\t01:00:01:00\t(1 1 1)(0 0 0)(1 1 1)\t0.9\t(1.2 1 1) (0 0 0) (1 1 1) (0.9000)\n"
+	decoder := NewDecoder(strings.NewReader(input))
+	timeline, err := decoder.Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode ALE: %v", err)
+	}
+
+	// ASC_SOP is trusted over CDL
+	cdl := timeline.FindClips(nil, false)[0].Metadata()[DefaultCDLMetadataKey].(*CDLData)
+	if cdl.ASCSOP.Slope[0] != 1 {
+		t.Errorf("Slope = %v, want ASC_SOP's", cdl.ASCSOP.Slope)
+	}
+
+	warnings := decoder.Warnings()
+	if len(warnings) != 1 {
+		t.Fatalf("Expected 1 warning, got %v", warnings)
+	}
+	var cellErr *CellError
+	if !errors.As(warnings[0], &cellErr) || cellErr.Column != ColumnCDL || !strings.Contains(cellErr.Error(), ColumnASCSOP) {
+		t.Errorf("Warning = %v, want a CDL conflict with ASC_SOP", warnings[0])
+	}
+}
+
+func TestDecoder_CDLInvalidCells(t *testing.T) {
+	// A001 has an unreadable ASC_SOP, A002 a negative saturation
+	input := "Heading\nFIELD_DELIM\tTABS\nFPS\t24\n\nColumn\nName\tStart\tEnd\tASC_SOP\tASC_SAT\n\nData\n" +
+		"A001\t01:00:00:00\t01:00:01:00\t(1 1)(0 0 0)(1 1 1)\t0.9\n" +
+		"A002\t01:00:01:00\t01:00:02:00\t(1 1 1)(0 0 0)(1 1 1)\t-0.5\n"
+	decoder := NewDecoder(strings.NewReader(input), WithSchema(DefaultSchema()))
+	timeline, err := decoder.Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode ALE: %v", err)
+	}
+
+	warnings := decoder.Warnings()
+	want := []struct {
+		row    int
+		column string
+	}{
+		{0, ColumnASCSOP},
+		{1, ColumnASCSat},
+	}
+	if len(warnings) != len(want) {
+		t.Fatalf("Warnings = %v, want %d", warnings, len(want))
+	}
+	for i, w := range want {
+		var cellErr *CellError
+		if !errors.As(warnings[i], &cellErr) || cellErr.Row != w.row || cellErr.Column != w.column || cellErr.Type != TypeCDL {
+			t.Errorf("Warning %d = %v, want a CDL error in row %d %s", i, warnings[i], w.row, w.column)
+		}
+	}
+
+	// The unreadable cell is kept as logged and written back
+	clip := timeline.FindClips(nil, false)[0]
+	if got := asMap(clip.Metadata()[DefaultMetadataKey])[ColumnASCSOP]; got != "(1 1)(0 0 0)(1 1 1)" {
+		t.Errorf("ASC_SOP metadata = %v, want the logged value", got)
+	}
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).Encode(timeline); err != nil {
+		t.Fatalf("Failed to encode timeline: %v", err)
+	}
+	if !strings.Contains(buf.String(), "(1 1)(0 0 0)(1 1 1)") {
+		t.Error("Output lost the logged ASC_SOP")
+	}
+}
+
+func TestEncoder_WithCDLFormat(t *testing.T) {
+	sat := 0.8
+	sop := &SOPValues{Slope: [3]float64{1.1, 1, 0.9}, Offset: [3]float64{0.01, 0, -0.02}, Power: [3]float64{1, 1.2, 1}}
+	timeline := cdlTimeline([]*CDLData{{ASCSOP: sop, ASCSat: &sat}})
+
+	tests := []struct {
+		format CDLFormat
+		want   map[string]string
+	}{
+		{CDLFormatASC, map[string]string{
+			ColumnASCSOP: "(1.1000 1.0000 0.9000)(0.0100 0.0000 -0.0200)(1.0000 1.2000 1.0000)",
+			ColumnASCSat: "0.8000",
+		}},
+		{CDLFormatCombined, map[string]string{
+			ColumnCDL: "(1.1000 1.0000 0.9000) (0.0100 0.0000 -0.0200) (1.0000 1.2000 1.0000) (0.8000)",
+		}},
+		{CDLFormatChannels, map[string]string{
+			ColumnASCSOPR: "(1.1000 0.0100 1.0000)",
+			ColumnASCSOPG: "(1.0000 0.0000 1.2000)",
+			ColumnASCSOPB: "(0.9000 -0.0200 1.0000)",
+			ColumnASCSat:  "0.8000",
+		}},
+		{CDLFormatSOP, map[string]string{
+			ColumnSlope:      "(1.1000 1.0000 0.9000)",
+			ColumnOffset:     "(0.0100 0.0000 -0.0200)",
+			ColumnPower:      "(1.0000 1.2000 1.0000)",
+			ColumnSaturation: "0.8000",
+		}},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := NewEncoder(&buf, WithCDLFormat(tt.format)).Encode(timeline); err != nil {
+			t.Fatalf("Failed to encode timeline: %v", err)
+		}
+		aleFile, err := NewDecoder(bytes.NewReader(buf.Bytes())).parseALE(t.Context())
+		if err != nil {
+			t.Fatalf("Failed to parse encoded ALE: %v", err)
+		}
+		if len(aleFile.Columns) != 5+len(tt.want) {
+			t.Errorf("Format %d columns = %v, want only %v added", tt.format, aleFile.Columns, tt.want)
+		}
+		row := aleFile.Row(0)
+		for col, value := range tt.want {
+			if got := row.Get(col); got != value {
+				t.Errorf("Format %d %s = %q, want %q", tt.format, col, got, value)
+			}
+		}
+
+		// Every format reads back to the same grade
+		decoded, err := NewDecoder(bytes.NewReader(buf.Bytes())).Decode()
+		if err != nil {
+			t.Fatalf("Failed to decode ALE: %v", err)
+		}
+		cdl := decoded.FindClips(nil, false)[0].Metadata()[DefaultCDLMetadataKey].(*CDLData)
+		if *cdl.ASCSOP != *sop || *cdl.ASCSat != sat {
+			t.Errorf("Format %d read back %+v, want %+v", tt.format, cdl, sop)
+		}
+	}
+}
+
+func TestEncoder_CDLColumnRoundTrip(t *testing.T) {
+	data, err := os.ReadFile("testdata/sample_cdl.ale")
+	if err != nil {
+		t.Fatalf("Failed to read test file: %v", err)
+	}
+	decoder := NewDecoder(bytes.NewReader(data))
+	timeline, err := decoder.Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode ALE: %v", err)
+	}
+	for _, warning := range decoder.Warnings() {
+		t.Errorf("Unexpected warning: %v", warning)
+	}
+
+	// Columns read into the grade are not kept as columns
+	columns := asMap(timeline.FindClips(nil, false)[0].Metadata()[DefaultMetadataKey])
+	if _, ok := columns[ColumnCDL]; ok {
+		t.Error("CDL column should only be stored as the grade")
+	}
+
+	// The logged CDL column is filled from the grade in its own spelling
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).Encode(timeline); err != nil {
+		t.Fatalf("Failed to encode timeline: %v", err)
+	}
+	want := "(0.8714 0.9334 0.9947) (-0.0870 -0.0922 -0.0808) (0.9988 1.0218 1.0101) (0.9000)"
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("Output missing CDL column %q", want)
+	}
+
+	// Unrelated Offset values are left alone
+	input := "Heading\nFIELD_DELIM\tTABS\nFPS\t24\n\nColumn\nName\tStart\tEnd\tASC_SAT\tOffset\n\nData\nA001\t01:00:00:00\t01:00:01:00\t0.9\t+12 frames\n"
+	timeline, err = NewDecoder(strings.NewReader(input)).Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode ALE: %v", err)
+	}
+	buf.Reset()
+	if err := NewEncoder(&buf).Encode(timeline); err != nil {
+		t.Fatalf("Failed to encode timeline: %v", err)
+	}
+	if !strings.Contains(buf.String(), "+12 frames") {
+		t.Errorf("Offset column was overwritten:\n%s", buf.String())
+	}
+}
+
+func TestDecoder_WithCDLEffect(t *testing.T) {
+	data, err := os.ReadFile("testdata/sample_cdl.ale")
+	if err != nil {
+		t.Fatalf("Failed to read test file: %v", err)
+	}
+	timeline, err := NewDecoder(bytes.NewReader(data), WithCDLEffect(true)).Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode ALE: %v", err)
+	}
+
+	clip := timeline.FindClips(nil, false)[0]
+	if _, ok := clip.Metadata()[DefaultCDLMetadataKey]; ok {
+		t.Error("Grade stored in metadata as well as an effect")
+	}
+	effects := clip.Effects()
+	if len(effects) != 1 || effects[0].EffectName() != CDLEffectName {
+		t.Fatalf("Clip effects = %v, want one %s effect", effects, CDLEffectName)
+	}
+	cdl, ok := effects[0].Metadata()["cdl"].(*CDLData)
+	if !ok || cdl.ASCSOP == nil || *cdl.ASCSat != 0.9 {
+		t.Errorf("Effect CDL = %+v", cdl)
+	}
+
+	// The encoder reads the grade from the effect
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).Encode(timeline); err != nil {
+		t.Fatalf("Failed to encode timeline: %v", err)
+	}
+	if !strings.Contains(buf.String(), "(0.8714 0.9334 0.9947)(-0.0870 -0.0922 -0.0808)(0.9988 1.0218 1.0101)") {
+		t.Errorf("Output missing ASC_SOP from effect:\n%s", buf.String())
+	}
+
+	// Imported grades replace the effect's grade
+	sat := 0.5
+	grades := []*CDLData{{ID: clip.Name(), ASCSat: &sat}}
+	if result := NewCDLImporter().ApplyToTimeline(timeline, grades); result.Matched == 0 {
+		t.Fatalf("Result = %+v, want a match", result)
+	}
+	if _, ok := clip.Metadata()[DefaultCDLMetadataKey]; ok {
+		t.Error("Imported grade stored in metadata instead of the effect")
+	}
+	if cdl := effects[0].Metadata()["cdl"].(*CDLData); *cdl.ASCSat != 0.5 {
+		t.Errorf("Effect saturation = %v, want 0.5", *cdl.ASCSat)
+	}
+}
+
+func TestEncoder_CDLEffectMap(t *testing.T) {
+	// An effect read back from .otio holds the grade as a map
+	effect := gotio.NewEffect("ASC CDL", CDLEffectName, gotio.AnyDictionary{
+		"cdl": map[string]interface{}{
+			"asc_sop": map[string]interface{}{
+				"slope":  []interface{}{1.1, 1.0, 0.9},
+				"offset": []interface{}{0.0, 0.0, 0.0},
+				"power":  []interface{}{1.0, 1.0, 1.0},
+			},
+			"asc_sat": 0.8,
+		},
+	})
+	sourceRange := opentime.NewTimeRange(opentime.NewRationalTime(0, 24), opentime.NewRationalTime(24, 24))
+	clip := gotio.NewClip("Clip", nil, &sourceRange, nil, []gotio.Effect{effect}, nil, "", nil)
+	timeline := gotio.NewTimeline("CDL Effect", nil, nil)
+	track := gotio.NewTrack("Video", nil, gotio.TrackKindVideo, nil, nil)
+	track.AppendChild(clip)
+	timeline.Tracks().AppendChild(track)
+
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).Encode(timeline); err != nil {
+		t.Fatalf("Failed to encode timeline: %v", err)
+	}
+	for _, want := range []string{"(1.1000 1.0000 0.9000)(0.0000 0.0000 0.0000)(1.0000 1.0000 1.0000)", "0.8000"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Output missing %q:\n%s", want, buf.String())
+		}
+	}
+}
@@ -0,0 +1,649 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package ale
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Avalanche-io/gotio"
+)
+
+// CDLNamespace is the XML namespace of ASC CDL files
+const CDLNamespace = "urn:ASC:CDL:v1.01"
+
+// CDLFileFormat is an ASC CDL XML file format
+type CDLFileFormat int
+
+// CDL file formats
+const (
+	// CDLFileCDL writes a ColorDecisionList (.cdl)
+	CDLFileCDL CDLFileFormat = iota
+	// CDLFileCC writes a single ColorCorrection (.cc)
+	CDLFileCC
+	// CDLFileCCC writes a ColorCorrectionCollection (.ccc)
+	CDLFileCCC
+)
+
+// Extension returns the file extension of the format
+func (f CDLFileFormat) Extension() string {
+	switch f {
+	case CDLFileCC:
+		return ".cc"
+	case CDLFileCCC:
+		return ".ccc"
+	}
+	return ".cdl"
+}
+
+// XML elements of the ASC CDL schema, in schema order
+type (
+	xmlColorDecisionList struct {
+		XMLName   xml.Name           `xml:"ColorDecisionList"`
+		Namespace string             `xml:"xmlns,attr"`
+		Decisions []xmlColorDecision `xml:"ColorDecision"`
+	}
+
+	xmlColorDecision struct {
+		Correction xmlColorCorrection `xml:"ColorCorrection"`
+	}
+
+	xmlColorCorrectionCollection struct {
+		XMLName     xml.Name             `xml:"ColorCorrectionCollection"`
+		Namespace   string               `xml:"xmlns,attr"`
+		Corrections []xmlColorCorrection `xml:"ColorCorrection"`
+	}
+
+	xmlColorCorrection struct {
+		XMLName            xml.Name    `xml:"ColorCorrection"`
+		Namespace          string      `xml:"xmlns,attr,omitempty"`
+		ID                 string      `xml:"id,attr"`
+		Descriptions       []string    `xml:"Description"`
+		InputDescription   string      `xml:"InputDescription,omitempty"`
+		ViewingDescription string      `xml:"ViewingDescription,omitempty"`
+		SOPNode            *xmlSOPNode `xml:"SOPNode"`
+		SatNode            *xmlSatNode `xml:"SatNode"`
+	}
+
+	xmlSOPNode struct {
+		Slope  string `xml:"Slope"`
+		Offset string `xml:"Offset"`
+		Power  string `xml:"Power"`
+	}
+
+	xmlSatNode struct {
+		Saturation string `xml:"Saturation"`
+	}
+)
+
+// CDLExporter writes clip grades as ASC CDL XML files
+type CDLExporter struct {
+	format    CDLFileFormat
+	cdlKey    string
+	precision int
+	clipNames bool
+}
+
+// CDLExportOption configures a CDLExporter
+type CDLExportOption func(*CDLExporter)
+
+// WithCDLFileFormat sets the file format (default: CDLFileCDL)
+func WithCDLFileFormat(format CDLFileFormat) CDLExportOption {
+	return func(x *CDLExporter) {
+		x.format = format
+	}
+}
+
+// WithExportCDLMetadataKey sets the clip metadata key grades are read from
+func WithExportCDLMetadataKey(key string) CDLExportOption {
+	return func(x *CDLExporter) {
+		x.cdlKey = key
+	}
+}
+
+// WithExportCDLPrecision writes values with a fixed number of decimals
+// instead of losslessly
+func WithExportCDLPrecision(digits int) CDLExportOption {
+	return func(x *CDLExporter) {
+		x.precision = digits
+	}
+}
+
+// WithClipNameIDs uses clip names as ColorCorrection ids even for clips
+// with a ColorCorr id
+func WithClipNameIDs(enabled bool) CDLExportOption {
+	return func(x *CDLExporter) {
+		x.clipNames = enabled
+	}
+}
+
+// NewCDLExporter creates a new ASC CDL exporter
+func NewCDLExporter(opts ...CDLExportOption) *CDLExporter {
+	x := &CDLExporter{
+		format:    CDLFileCDL,
+		cdlKey:    DefaultCDLMetadataKey,
+		precision: DefaultCDLPrecision,
+	}
+	for _, opt := range opts {
+		opt(x)
+	}
+	return x
+}
+
+// TimelineGrades returns the grades of a timeline's clips in order. Each
+// grade is identified by its ColorCorr id, or by the clip name. Clips
+// without a slope/offset/power or saturation are skipped.
+func (x *CDLExporter) TimelineGrades(timeline *gotio.Timeline) []*CDLData {
+	var grades []*CDLData
+	for _, clip := range timeline.FindClips(nil, false) {
+		if grade := x.grade(clipCDL(clip, x.cdlKey), clip.Name()); grade != nil {
+			grades = append(grades, grade)
+		}
+	}
+	return grades
+}
+
+// ALEGrades returns the grades of an ALE file's rows in order, identified
+// by their ColorCorr id or Name
+func (x *CDLExporter) ALEGrades(aleFile *ALEFile) []*CDLData {
+	var grades []*CDLData
+	for i := range aleFile.Rows {
+		row := aleFile.Row(i)
+		cdl, _, _ := rowCDL(row, i)
+		if grade := x.grade(cdl, row.Get(ColumnName)); grade != nil {
+			grades = append(grades, grade)
+		}
+	}
+	return grades
+}
+
+// grade returns a copy of a clip's CDL identified for export, or nil if it
+// has no values
+func (x *CDLExporter) grade(cdl *CDLData, name string) *CDLData {
+	if cdl == nil || (cdl.ASCSOP == nil && cdl.ASCSat == nil) {
+		return nil
+	}
+	grade := *cdl
+	if grade.ID == "" || x.clipNames {
+		grade.ID = name
+	}
+	return &grade
+}
+
+// Write writes grades as one document: a ColorDecisionList with a
+// decision per grade, a ColorCorrectionCollection, or for CDLFileCC a
+// single ColorCorrection. Grades sharing an id are written once; grades
+// are validated first and nothing is written if any is invalid.
+func (x *CDLExporter) Write(w io.Writer, grades []*CDLData) error {
+	grades, err := uniqueGrades(grades)
+	if err != nil {
+		return err
+	}
+	if x.format == CDLFileCC && len(grades) != 1 {
+		return fmt.Errorf("a .cc file holds one ColorCorrection, got %d", len(grades))
+	}
+
+	corrections := make([]xmlColorCorrection, len(grades))
+	for i, grade := range grades {
+		correction, err := x.correction(grade)
+		if err != nil {
+			return err
+		}
+		corrections[i] = correction
+	}
+
+	var doc interface{}
+	switch x.format {
+	case CDLFileCC:
+		corrections[0].Namespace = CDLNamespace
+		doc = corrections[0]
+	case CDLFileCCC:
+		doc = xmlColorCorrectionCollection{Namespace: CDLNamespace, Corrections: corrections}
+	default:
+		list := xmlColorDecisionList{Namespace: CDLNamespace}
+		for _, correction := range corrections {
+			list.Decisions = append(list.Decisions, xmlColorDecision{Correction: correction})
+		}
+		doc = list
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	encoder := xml.NewEncoder(&buf)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("failed to write CDL XML: %w", err)
+	}
+	buf.WriteByte('\n')
+
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// ExportFiles writes grades to dir: a .cdl or .cc file per grade named
+// after its id, or for CDLFileCCC a single name.ccc. It returns the paths
+// written.
+func (x *CDLExporter) ExportFiles(dir, name string, grades []*CDLData) ([]string, error) {
+	grades, err := uniqueGrades(grades)
+	if err != nil {
+		return nil, err
+	}
+
+	if x.format == CDLFileCCC {
+		path := filepath.Join(dir, cdlFileName(name)+x.format.Extension())
+		if err := x.writeFile(path, grades); err != nil {
+			return nil, err
+		}
+		return []string{path}, nil
+	}
+
+	// Validate everything before writing any file
+	for _, grade := range grades {
+		if _, err := x.correction(grade); err != nil {
+			return nil, err
+		}
+	}
+
+	paths := make([]string, 0, len(grades))
+	used := make(map[string]bool)
+	for _, grade := range grades {
+		base := cdlFileName(grade.ID)
+		for n := 2; used[strings.ToLower(base)]; n++ {
+			base = fmt.Sprintf("%s_%d", cdlFileName(grade.ID), n)
+		}
+		used[strings.ToLower(base)] = true
+
+		path := filepath.Join(dir, base+x.format.Extension())
+		if err := x.writeFile(path, []*CDLData{grade}); err != nil {
+			return paths, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// writeFile writes grades as one document to path
+func (x *CDLExporter) writeFile(path string, grades []*CDLData) error {
+	var buf bytes.Buffer
+	if err := x.Write(&buf, grades); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// correction validates a grade against the ASC CDL schema structure and
+// returns its ColorCorrection element
+func (x *CDLExporter) correction(grade *CDLData) (xmlColorCorrection, error) {
+	if strings.TrimSpace(grade.ID) == "" {
+		return xmlColorCorrection{}, errors.New("ColorCorrection requires an id")
+	}
+	if grade.ASCSOP == nil && grade.ASCSat == nil {
+		return xmlColorCorrection{}, fmt.Errorf("ColorCorrection '%s' requires a SOPNode or SatNode", grade.ID)
+	}
+	if err := grade.Validate(); err != nil {
+		return xmlColorCorrection{}, err
+	}
+
+	correction := xmlColorCorrection{
+		ID:                 grade.ID,
+		Descriptions:       grade.Descriptions,
+		InputDescription:   grade.InputDescription,
+		ViewingDescription: grade.ViewingDescription,
+	}
+	if grade.ASCSOP != nil {
+		correction.SOPNode = &xmlSOPNode{
+			Slope:  x.formatTriple(grade.ASCSOP.Slope),
+			Offset: x.formatTriple(grade.ASCSOP.Offset),
+			Power:  x.formatTriple(grade.ASCSOP.Power),
+		}
+	}
+	if grade.ASCSat != nil {
+		correction.SatNode = &xmlSatNode{Saturation: formatCDLValue(*grade.ASCSat, x.precision)}
+	}
+	return correction, nil
+}
+
+// formatTriple formats three values separated by spaces
+func (x *CDLExporter) formatTriple(values [3]float64) string {
+	parts := make([]string, 3)
+	for i, v := range values {
+		parts[i] = formatCDLValue(v, x.precision)
+	}
+	return strings.Join(parts, " ")
+}
+
+// uniqueGrades drops repeated grades that share an id, as clips sharing a
+// ColorCorr id do, and reports ids shared by different grades
+func uniqueGrades(grades []*CDLData) ([]*CDLData, error) {
+	seen := make(map[string]*CDLData)
+	var unique []*CDLData
+	for _, grade := range grades {
+		first, ok := seen[grade.ID]
+		if !ok {
+			seen[grade.ID] = grade
+			unique = append(unique, grade)
+			continue
+		}
+		if !sameGrade(first, grade) {
+			return nil, fmt.Errorf("ColorCorrection id '%s' is used by different grades", grade.ID)
+		}
+	}
+	return unique, nil
+}
+
+// sameGrade reports whether two CDLs have the same values
+func sameGrade(a, b *CDLData) bool {
+	if (a.ASCSOP == nil) != (b.ASCSOP == nil) || (a.ASCSat == nil) != (b.ASCSat == nil) {
+		return false
+	}
+	if a.ASCSOP != nil && *a.ASCSOP != *b.ASCSOP {
+		return false
+	}
+	return a.ASCSat == nil || *a.ASCSat == *b.ASCSat
+}
+
+// cdlFileName makes an id safe to use as a file name
+func cdlFileName(id string) string {
+	name := strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|':
+			return '_'
+		}
+		if r < ' ' {
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(id))
+	if name == "" || name == "." || name == ".." {
+		return "grade"
+	}
+	return name
+}
+
+// ReadCDLXML reads the ColorCorrections of an ASC CDL XML document: a
+// ColorDecisionList (.cdl), a ColorCorrectionCollection (.ccc) or a single
+// ColorCorrection (.cc). Input and viewing descriptions of a list or
+// collection apply to corrections that have none of their own.
+func ReadCDLXML(r io.Reader) ([]*CDLData, error) {
+	decoder := xml.NewDecoder(r)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil, errors.New("no ASC CDL element found")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CDL XML: %w", err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		var doc xmlCDLDocument
+		switch start.Name.Local {
+		case "ColorCorrection":
+			var correction xmlColorCorrection
+			if err := decoder.DecodeElement(&correction, &start); err != nil {
+				return nil, fmt.Errorf("failed to read ColorCorrection: %w", err)
+			}
+			doc.Corrections = []xmlColorCorrection{correction}
+		case "ColorCorrectionCollection", "ColorDecisionList":
+			if err := decoder.DecodeElement(&doc, &start); err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", start.Name.Local, err)
+			}
+			for _, decision := range doc.Decisions {
+				doc.Corrections = append(doc.Corrections, decision.Correction)
+			}
+		default:
+			return nil, fmt.Errorf("unexpected root element %s", start.Name.Local)
+		}
+
+		grades := make([]*CDLData, 0, len(doc.Corrections))
+		for _, correction := range doc.Corrections {
+			grade, err := correction.grade()
+			if err != nil {
+				return nil, err
+			}
+			if grade.InputDescription == "" {
+				grade.InputDescription = doc.InputDescription
+			}
+			if grade.ViewingDescription == "" {
+				grade.ViewingDescription = doc.ViewingDescription
+			}
+			grades = append(grades, grade)
+		}
+		return grades, nil
+	}
+}
+
+// xmlCDLDocument reads a ColorDecisionList or ColorCorrectionCollection
+type xmlCDLDocument struct {
+	InputDescription   string               `xml:"InputDescription"`
+	ViewingDescription string               `xml:"ViewingDescription"`
+	Corrections        []xmlColorCorrection `xml:"ColorCorrection"`
+	Decisions          []xmlColorDecision   `xml:"ColorDecision"`
+}
+
+// grade converts a ColorCorrection element to CDL data
+func (c xmlColorCorrection) grade() (*CDLData, error) {
+	grade := &CDLData{
+		ID:                 strings.TrimSpace(c.ID),
+		Descriptions:       c.Descriptions,
+		InputDescription:   strings.TrimSpace(c.InputDescription),
+		ViewingDescription: strings.TrimSpace(c.ViewingDescription),
+	}
+	if c.SOPNode != nil {
+		sop := &SOPValues{}
+		for _, node := range []struct {
+			name   string
+			value  string
+			target *[3]float64
+		}{
+			{"Slope", c.SOPNode.Slope, &sop.Slope},
+			{"Offset", c.SOPNode.Offset, &sop.Offset},
+			{"Power", c.SOPNode.Power, &sop.Power},
+		} {
+			values, ok := parseCDLTriple(node.value)
+			if !ok {
+				return nil, fmt.Errorf("ColorCorrection '%s' has an invalid %s '%s'", grade.ID, node.name, strings.TrimSpace(node.value))
+			}
+			*node.target = values
+		}
+		grade.ASCSOP = sop
+	}
+	if c.SatNode != nil {
+		sat, ok := parseCDLNumber(c.SatNode.Saturation)
+		if !ok {
+			return nil, fmt.Errorf("ColorCorrection '%s' has an invalid Saturation '%s'", grade.ID, strings.TrimSpace(c.SatNode.Saturation))
+		}
+		grade.ASCSat = &sat
+	}
+	return grade, nil
+}
+
+// CDLImportResult reports how imported grades were matched
+type CDLImportResult struct {
+	// Matched is the number of clips or rows given a grade
+	Matched int
+	// UnmatchedIDs are the ColorCorrection ids no clip matched
+	UnmatchedIDs []string
+	// UnmatchedClips are the names of clips that matched no grade
+	UnmatchedClips []string
+}
+
+// CDLImporter fills clip grades from ASC CDL XML
+type CDLImporter struct {
+	column      string
+	metadataKey string
+	cdlKey      string
+	layout      MetadataLayout
+	precision   int
+}
+
+// CDLImportOption configures a CDLImporter
+type CDLImportOption func(*CDLImporter)
+
+// WithCDLMatchColumn matches ColorCorrection ids against one column, such
+// as ColumnName, ColumnColorCorrID or a custom key column. By default a
+// clip matches by its ColorCorr id or, failing that, its name.
+func WithCDLMatchColumn(column string) CDLImportOption {
+	return func(m *CDLImporter) {
+		m.column = column
+	}
+}
+
+// WithImportMetadataKey sets the clip metadata key key columns are read
+// from, as set by WithMetadataKey on the decoder
+func WithImportMetadataKey(key string) CDLImportOption {
+	return func(m *CDLImporter) {
+		m.metadataKey = key
+	}
+}
+
+// WithImportCDLMetadataKey sets the clip metadata key grades are written to
+func WithImportCDLMetadataKey(key string) CDLImportOption {
+	return func(m *CDLImporter) {
+		m.cdlKey = key
+	}
+}
+
+// WithImportMetadataLayout sets how columns are arranged under the
+// metadata key
+func WithImportMetadataLayout(layout MetadataLayout) CDLImportOption {
+	return func(m *CDLImporter) {
+		m.layout = layout
+	}
+}
+
+// WithImportCDLPrecision writes ASC_SOP and ASC_SAT with a fixed number of
+// decimals when filling an ALEFile
+func WithImportCDLPrecision(digits int) CDLImportOption {
+	return func(m *CDLImporter) {
+		m.precision = digits
+	}
+}
+
+// NewCDLImporter creates a new ASC CDL importer
+func NewCDLImporter(opts ...CDLImportOption) *CDLImporter {
+	m := &CDLImporter{
+		metadataKey: DefaultMetadataKey,
+		cdlKey:      DefaultCDLMetadataKey,
+		layout:      LayoutFlat,
+		precision:   DefaultCDLPrecision,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// ApplyToTimeline writes matching grades into the clips of a timeline,
+// replacing the grade in their metadata or ASC CDL Effect
+func (m *CDLImporter) ApplyToTimeline(timeline *gotio.Timeline, grades []*CDLData) CDLImportResult {
+	clips := timeline.FindClips(nil, false)
+	return m.apply(grades, len(clips), func(i int) (string, func(string) string) {
+		clip := clips[i]
+		metadata := clip.Metadata()
+		return clip.Name(), func(column string) string {
+			switch column {
+			case ColumnName:
+				return clip.Name()
+			case ColumnColorCorrID:
+				if cdl := clipCDL(clip, m.cdlKey); cdl != nil && cdl.ID != "" {
+					return cdl.ID
+				}
+			}
+			if value, ok := columnMetadata(metadata, m.metadataKey, m.layout)[column]; ok {
+				return formatCell(ColumnSpec{}, value)
+			}
+			return ""
+		}
+	}, func(i int, grade *CDLData) {
+		imported := *grade
+		setClipCDL(clips[i], m.cdlKey, &imported)
+	})
+}
+
+// ApplyToALE fills the ASC_SOP and ASC_SAT columns of matching rows,
+// adding the columns if needed, and every other CDL column of the file
+// from the same grade, clearing the parts the grade does not set. An
+// empty ColorCorr id is filled with the matched id.
+func (m *CDLImporter) ApplyToALE(aleFile *ALEFile, grades []*CDLData) CDLImportResult {
+	return m.apply(grades, len(aleFile.Rows), func(i int) (string, func(string) string) {
+		row := aleFile.Row(i)
+		return row.Get(ColumnName), row.Get
+	}, func(i int, grade *CDLData) {
+		for _, col := range cdlGradeColumns {
+			_, present := aleFile.ColumnIndex(col)
+			graded, ok := grade.gradeValue(col, m.precision)
+			if !present && !(ok && (col == ColumnASCSOP || col == ColumnASCSat)) {
+				continue
+			}
+			// Leave unrelated Slope, Offset, Power or Saturation values
+			if value := aleFile.Row(i).Get(col); value != "" && !readsAsGrade(col, value) {
+				continue
+			}
+			aleFile.Set(i, col, graded)
+		}
+		if _, ok := aleFile.ColumnIndex(ColumnColorCorrID); ok && aleFile.Row(i).Get(ColumnColorCorrID) == "" {
+			aleFile.Set(i, ColumnColorCorrID, grade.ID)
+		}
+	})
+}
+
+// apply matches grades to n clips or rows by id. item returns the name of
+// clip i and a column lookup; set applies a grade to it.
+func (m *CDLImporter) apply(grades []*CDLData, n int, item func(int) (string, func(string) string), set func(int, *CDLData)) CDLImportResult {
+	byID := make(map[string]*CDLData, len(grades))
+	for _, grade := range grades {
+		if _, ok := byID[grade.ID]; !ok {
+			byID[grade.ID] = grade
+		}
+	}
+
+	var result CDLImportResult
+	used := make(map[string]bool)
+	for i := range n {
+		name, column := item(i)
+		var keys []string
+		if m.column != "" {
+			keys = []string{column(m.column)}
+		} else {
+			keys = []string{column(ColumnColorCorrID), name}
+		}
+
+		matched := false
+		for _, key := range keys {
+			key = strings.TrimSpace(key)
+			if grade, ok := byID[key]; ok && key != "" {
+				set(i, grade)
+				used[key] = true
+				matched = true
+				break
+			}
+		}
+		if matched {
+			result.Matched++
+		} else {
+			result.UnmatchedClips = append(result.UnmatchedClips, name)
+		}
+	}
+
+	for _, grade := range grades {
+		if !used[grade.ID] {
+			used[grade.ID] = true
+			result.UnmatchedIDs = append(result.UnmatchedIDs, grade.ID)
+		}
+	}
+	return result
+}
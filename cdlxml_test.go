@@ -0,0 +1,437 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package ale
+
+import (
+	"bytes"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Avalanche-io/gotio"
+	"github.com/Avalanche-io/gotio/opentime"
+)
+
+func TestCDLExporter_Write(t *testing.T) {
+	sat := 0.9
+	grades := []*CDLData{
+		{
+			ID:           "A001C001",
+			Descriptions: []string{"Day look"},
+			ASCSOP:       &SOPValues{Slope: [3]float64{1.1, 1, 0.9}, Offset: [3]float64{0.01, 0, -0.02}, Power: [3]float64{1, 1, 1}},
+			ASCSat:       &sat,
+		},
+		{ID: "A001C002", ASCSat: &sat},
+	}
+
+	var buf bytes.Buffer
+	if err := NewCDLExporter(WithCDLFileFormat(CDLFileCCC)).Write(&buf, grades); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	output := buf.String()
+	if !strings.HasPrefix(output, xml.Header) {
+		t.Error("Output missing XML header")
+	}
+
+	var collection xmlColorCorrectionCollection
+	if err := xml.Unmarshal(buf.Bytes(), &collection); err != nil {
+		t.Fatalf("Failed to parse output: %v", err)
+	}
+	if collection.XMLName.Space != CDLNamespace || len(collection.Corrections) != 2 {
+		t.Fatalf("Collection = %+v, want 2 corrections in %s", collection, CDLNamespace)
+	}
+	first := collection.Corrections[0]
+	if first.ID != "A001C001" || first.SOPNode == nil || first.SOPNode.Slope != "1.1000 1.0000 0.9000" || first.SatNode.Saturation != "0.9000" {
+		t.Errorf("First correction = %+v", first)
+	}
+	if second := collection.Corrections[1]; second.SOPNode != nil || second.SatNode == nil {
+		t.Errorf("Second correction = %+v, want only a SatNode", second)
+	}
+
+	// Elements follow the schema order
+	for _, order := range [][2]string{{"<Description>", "<SOPNode>"}, {"<SOPNode>", "<SatNode>"}, {"<Slope>", "<Offset>"}, {"<Offset>", "<Power>"}} {
+		if strings.Index(output, order[0]) > strings.Index(output, order[1]) {
+			t.Errorf("%s is written after %s", order[0], order[1])
+		}
+	}
+
+	// A .cc file holds one correction
+	if err := NewCDLExporter(WithCDLFileFormat(CDLFileCC)).Write(&buf, grades); err == nil {
+		t.Error("Write() of two grades as .cc = nil error, want an error")
+	}
+}
+
+func TestCDLExporter_Validation(t *testing.T) {
+	sat := 1.0
+	negative := -1.0
+	tests := []struct {
+		name   string
+		grades []*CDLData
+	}{
+		{"missing id", []*CDLData{{ASCSat: &sat}}},
+		{"no nodes", []*CDLData{{ID: "A"}}},
+		{"out of range", []*CDLData{{ID: "A", ASCSat: &negative}}},
+		{"shared id", []*CDLData{{ID: "A", ASCSat: &sat}, {ID: "A", ASCSat: &negative}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := NewCDLExporter().Write(&buf, tt.grades); err == nil {
+				t.Error("Write() = nil error, want an error")
+			}
+			if buf.Len() != 0 {
+				t.Errorf("Write() wrote %d bytes for invalid grades", buf.Len())
+			}
+		})
+	}
+}
+
+func TestCDLExporter_ExportFiles(t *testing.T) {
+	data, err := os.ReadFile("testdata/sample_cdl.ale")
+	if err != nil {
+		t.Fatalf("Failed to read test file: %v", err)
+	}
+	timeline, err := NewDecoder(bytes.NewReader(data)).Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode ALE: %v", err)
+	}
+
+	exporter := NewCDLExporter()
+	grades := exporter.TimelineGrades(timeline)
+	if len(grades) == 0 {
+		t.Fatal("No grades found")
+	}
+
+	dir := t.TempDir()
+	paths, err := exporter.ExportFiles(dir, "", grades)
+	if err != nil {
+		t.Fatalf("ExportFiles() error = %v", err)
+	}
+	// Takes of the same clip share a grade and a file
+	if len(paths) != 2 {
+		t.Fatalf("Wrote %v, want a file per clip name", paths)
+	}
+	clip := timeline.FindClips(nil, false)[0]
+	if want := filepath.Join(dir, clip.Name()+".cdl"); paths[0] != want {
+		t.Errorf("First file = %s, want %s", paths[0], want)
+	}
+
+	content, err := os.ReadFile(paths[0])
+	if err != nil {
+		t.Fatalf("Failed to read exported file: %v", err)
+	}
+	var list xmlColorDecisionList
+	if err := xml.Unmarshal(content, &list); err != nil {
+		t.Fatalf("Failed to parse exported file: %v", err)
+	}
+	correction := list.Decisions[0].Correction
+	if correction.ID != clip.Name() || correction.SOPNode.Slope != "0.8714 0.9334 0.9947" {
+		t.Errorf("Correction = %+v", correction)
+	}
+
+	// A collection is a single file
+	paths, err = NewCDLExporter(WithCDLFileFormat(CDLFileCCC)).ExportFiles(dir, "dailies", grades)
+	if err != nil {
+		t.Fatalf("ExportFiles() error = %v", err)
+	}
+	if len(paths) != 1 || paths[0] != filepath.Join(dir, "dailies.ccc") {
+		t.Errorf("Paths = %v, want dailies.ccc", paths)
+	}
+}
+
+func TestCDLExporter_ALEGrades(t *testing.T) {
+	input := "Heading\nFIELD_DELIM\tTABS\nFPS\t24\n\nColumn\nName\tStart\tEnd\tColorCorr id\tASC_SAT\n\nData\n" +
+		"A001\t01:00:00:00\t01:00:01:00\tX2\t0.9\n" +
+		"A002\t01:00:01:00\t01:00:02:00\tX2\t0.9\n" +
+		"A003\t01:00:02:00\t01:00:03:00\t\t0.8\n" +
+		"A004\t01:00:03:00\t01:00:04:00\tX3\t\n"
+	aleFile, err := ReadALE(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Failed to read ALE: %v", err)
+	}
+
+	grades := NewCDLExporter().ALEGrades(aleFile)
+	var ids []string
+	for _, grade := range grades {
+		ids = append(ids, grade.ID)
+	}
+	// A004 has an id but no grade
+	if got := strings.Join(ids, ","); got != "X2,X2,A003" {
+		t.Errorf("Grade ids = %s, want X2,X2,A003", got)
+	}
+
+	// Clips sharing a ColorCorr id share one file
+	paths, err := NewCDLExporter(WithCDLFileFormat(CDLFileCC)).ExportFiles(t.TempDir(), "", grades)
+	if err != nil {
+		t.Fatalf("ExportFiles() error = %v", err)
+	}
+	if len(paths) != 2 {
+		t.Errorf("Paths = %v, want one per id", paths)
+	}
+
+	names := NewCDLExporter(WithClipNameIDs(true)).ALEGrades(aleFile)
+	if names[0].ID != "A001" {
+		t.Errorf("Grade id = %s, want the clip name", names[0].ID)
+	}
+}
+
+const importCCC = `<?xml version="1.0" encoding="UTF-8"?>
+<ColorCorrectionCollection xmlns="urn:ASC:CDL:v1.01">
+  <InputDescription>ARRI LogC</InputDescription>
+  <ColorCorrection id="A001C001">
+    <Description>Day look</Description>
+    <SOPNode>
+      <Description>Warmer</Description>
+      <Slope>1.1 1.0 0.9</Slope>
+      <Offset>0.01 0.0 -0.02</Offset>
+      <Power>1.0 1.0 1.0</Power>
+    </SOPNode>
+    <SatNode>
+      <Saturation>0.85</Saturation>
+    </SatNode>
+  </ColorCorrection>
+  <ColorCorrection id="X2">
+    <SatNode>
+      <Saturation>0.5</Saturation>
+    </SatNode>
+  </ColorCorrection>
+  <ColorCorrection id="Z9">
+    <SatNode>
+      <Saturation>1.0</Saturation>
+    </SatNode>
+  </ColorCorrection>
+</ColorCorrectionCollection>
+`
+
+func TestReadCDLXML(t *testing.T) {
+	grades, err := ReadCDLXML(strings.NewReader(importCCC))
+	if err != nil {
+		t.Fatalf("ReadCDLXML() error = %v", err)
+	}
+	if len(grades) != 3 {
+		t.Fatalf("Expected 3 grades, got %d", len(grades))
+	}
+	first := grades[0]
+	want := SOPValues{Slope: [3]float64{1.1, 1, 0.9}, Offset: [3]float64{0.01, 0, -0.02}, Power: [3]float64{1, 1, 1}}
+	if first.ID != "A001C001" || *first.ASCSOP != want || *first.ASCSat != 0.85 {
+		t.Errorf("First grade = %+v", first)
+	}
+	if first.InputDescription != "ARRI LogC" || len(first.Descriptions) != 1 {
+		t.Errorf("First grade descriptions = %v, input %q", first.Descriptions, first.InputDescription)
+	}
+
+	// Exported files read back to the same grades
+	for _, format := range []CDLFileFormat{CDLFileCDL, CDLFileCCC} {
+		var buf bytes.Buffer
+		if err := NewCDLExporter(WithCDLFileFormat(format)).Write(&buf, grades); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		read, err := ReadCDLXML(&buf)
+		if err != nil {
+			t.Fatalf("ReadCDLXML() of %s error = %v", format.Extension(), err)
+		}
+		if len(read) != 3 || *read[0].ASCSOP != want || *read[1].ASCSat != 0.5 {
+			t.Errorf("%s read back %+v", format.Extension(), read)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := NewCDLExporter(WithCDLFileFormat(CDLFileCC)).Write(&buf, grades[:1]); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if read, err := ReadCDLXML(&buf); err != nil || len(read) != 1 || read[0].ID != "A001C001" {
+		t.Errorf("ReadCDLXML() of .cc = %v, %v", read, err)
+	}
+
+	invalid := strings.Replace(importCCC, "<Slope>1.1 1.0 0.9</Slope>", "<Slope>1.1 1.0</Slope>", 1)
+	if _, err := ReadCDLXML(strings.NewReader(invalid)); err == nil || !strings.Contains(err.Error(), "Slope") {
+		t.Errorf("ReadCDLXML() of a short slope = %v, want a Slope error", err)
+	}
+	if _, err := ReadCDLXML(strings.NewReader("<Timeline/>")); err == nil {
+		t.Error("ReadCDLXML() of another document = nil error, want an error")
+	}
+}
+
+func TestCDLImporter_ApplyToTimeline(t *testing.T) {
+	input := "Heading\nFIELD_DELIM\tTABS\nFPS\t24\n\nColumn\nName\tStart\tEnd\tColorCorr id\tShot\n\nData\n" +
+		"A001C001\t01:00:00:00\t01:00:01:00\t\tsh010\n" +
+		"A001C002\t01:00:01:00\t01:00:02:00\tX2\tsh020\n" +
+		"A001C003\t01:00:02:00\t01:00:03:00\t\tsh030\n"
+	timeline, err := NewDecoder(strings.NewReader(input)).Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode ALE: %v", err)
+	}
+	grades, err := ReadCDLXML(strings.NewReader(importCCC))
+	if err != nil {
+		t.Fatalf("ReadCDLXML() error = %v", err)
+	}
+
+	result := NewCDLImporter().ApplyToTimeline(timeline, grades)
+	if result.Matched != 2 {
+		t.Errorf("Matched = %d, want 2", result.Matched)
+	}
+	if strings.Join(result.UnmatchedClips, ",") != "A001C003" || strings.Join(result.UnmatchedIDs, ",") != "Z9" {
+		t.Errorf("Unmatched clips %v, ids %v; want A001C003 and Z9", result.UnmatchedClips, result.UnmatchedIDs)
+	}
+
+	clips := timeline.FindClips(nil, false)
+	cdl, ok := clips[1].Metadata()[DefaultCDLMetadataKey].(*CDLData)
+	if !ok || cdl.ID != "X2" || *cdl.ASCSat != 0.5 {
+		t.Errorf("Clip matched by ColorCorr id has CDL %+v", cdl)
+	}
+
+	// The grades are written as ASC columns
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).Encode(timeline); err != nil {
+		t.Fatalf("Failed to encode timeline: %v", err)
+	}
+	if !strings.Contains(buf.String(), "(1.1000 1.0000 0.9000)(0.0100 0.0000 -0.0200)(1.0000 1.0000 1.0000)") {
+		t.Errorf("Output missing imported ASC_SOP:\n%s", buf.String())
+	}
+
+	// A key column matches custom ids
+	keyed := strings.Replace(importCCC, `id="A001C001"`, `id="sh030"`, 1)
+	grades, err = ReadCDLXML(strings.NewReader(keyed))
+	if err != nil {
+		t.Fatalf("ReadCDLXML() error = %v", err)
+	}
+	result = NewCDLImporter(WithCDLMatchColumn("Shot")).ApplyToTimeline(timeline, grades)
+	if result.Matched != 1 || len(result.UnmatchedIDs) != 2 {
+		t.Errorf("Result = %+v, want one match by Shot", result)
+	}
+	if cdl := clips[2].Metadata()[DefaultCDLMetadataKey].(*CDLData); cdl.ID != "sh030" {
+		t.Errorf("Clip matched by Shot has CDL id %s", cdl.ID)
+	}
+}
+
+func TestCDLImporter_ApplyToALE(t *testing.T) {
+	input := "Heading\nFIELD_DELIM\tTABS\nFPS\t24\n\nColumn\nName\tStart\tEnd\tColorCorr id\n\nData\n" +
+		"A001C001\t01:00:00:00\t01:00:01:00\t\n" +
+		"B001C001\t01:00:01:00\t01:00:02:00\t\n"
+	aleFile, err := NewDecoder(strings.NewReader(input)).parseALE(t.Context())
+	if err != nil {
+		t.Fatalf("Failed to parse ALE: %v", err)
+	}
+	grades, err := ReadCDLXML(strings.NewReader(importCCC))
+	if err != nil {
+		t.Fatalf("ReadCDLXML() error = %v", err)
+	}
+
+	result := NewCDLImporter().ApplyToALE(aleFile, grades)
+	if result.Matched != 1 || strings.Join(result.UnmatchedClips, ",") != "B001C001" {
+		t.Errorf("Result = %+v", result)
+	}
+
+	row := aleFile.Row(0)
+	want := map[string]string{
+		ColumnASCSOP:      "(1.1000 1.0000 0.9000)(0.0100 0.0000 -0.0200)(1.0000 1.0000 1.0000)",
+		ColumnASCSat:      "0.8500",
+		ColumnColorCorrID: "A001C001",
+	}
+	for col, value := range want {
+		if got := row.Get(col); got != value {
+			t.Errorf("%s = %q, want %q", col, got, value)
+		}
+	}
+	if got := aleFile.Row(1).Get(ColumnASCSOP); got != "" {
+		t.Errorf("Unmatched row ASC_SOP = %q, want empty", got)
+	}
+
+	// Added columns are written in every row
+	var buf bytes.Buffer
+	if _, err := aleFile.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo() error = %v", err)
+	}
+	for i, row := range aleFile.Rows {
+		if len(row) != len(aleFile.Columns) {
+			t.Errorf("Row %d has %d fields, want %d", i, len(row), len(aleFile.Columns))
+		}
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	for _, line := range lines[len(lines)-2:] {
+		if fields := strings.Count(line, "\t") + 1; fields != len(aleFile.Columns) {
+			t.Errorf("Written row %q has %d fields, want %d", line, fields, len(aleFile.Columns))
+		}
+	}
+}
+
+func TestCDLImporter_ApplyToALEColumns(t *testing.T) {
+	// A001 is regraded with saturation only, A002 with a full grade
+	input := "Heading\nFIELD_DELIM\tTABS\nFPS\t24\n\nColumn\nName\tStart\tEnd\tASC_SOP\tCDL\tSlope\tOffset\tPower\tSaturation\n\nData\n" +
+		"A001\t01:00:00:00\t01:00:01:00\t(2 2 2)(0 0 0)(1 1 1)\t(2 2 2) (0 0 0) (1 1 1) (1)\t(2 2 2)\t(0 0 0)\t(1 1 1)\tHigh\n" +
+		"A002\t01:00:01:00\t01:00:02:00\t\t\t\t+12 frames\t\t\n"
+	aleFile, err := ReadALE(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Failed to read ALE: %v", err)
+	}
+	sat := 0.5
+	grades := []*CDLData{
+		{ID: "A001", ASCSat: &sat},
+		{ID: "A002", ASCSOP: &SOPValues{Slope: [3]float64{1.1, 1, 1}, Power: [3]float64{1, 1, 1}}, ASCSat: &sat},
+	}
+	if result := NewCDLImporter().ApplyToALE(aleFile, grades); result.Matched != 2 {
+		t.Fatalf("Result = %+v, want 2 matches", result)
+	}
+
+	want := []map[string]string{
+		{
+			ColumnASCSOP:     "",
+			ColumnASCSat:     "0.5000",
+			ColumnCDL:        "(1.0000 1.0000 1.0000) (0.0000 0.0000 0.0000) (1.0000 1.0000 1.0000) (0.5000)",
+			ColumnSlope:      "",
+			ColumnOffset:     "",
+			ColumnPower:      "",
+			ColumnSaturation: "High",
+		},
+		{
+			ColumnASCSOP:     "(1.1000 1.0000 1.0000)(0.0000 0.0000 0.0000)(1.0000 1.0000 1.0000)",
+			ColumnASCSat:     "0.5000",
+			ColumnCDL:        "(1.1000 1.0000 1.0000) (0.0000 0.0000 0.0000) (1.0000 1.0000 1.0000) (0.5000)",
+			ColumnSlope:      "(1.1000 1.0000 1.0000)",
+			ColumnOffset:     "+12 frames",
+			ColumnPower:      "(1.0000 1.0000 1.0000)",
+			ColumnSaturation: "0.5000",
+		},
+	}
+	for i, row := range want {
+		for col, value := range row {
+			if got := aleFile.Row(i).Get(col); got != value {
+				t.Errorf("Row %d %s = %q, want %q", i, col, got, value)
+			}
+		}
+	}
+}
+
+func TestCDLImporter_ApplyToTimelineOrder(t *testing.T) {
+	// Grades are replaced where the encoder reads them: the clip metadata
+	// first, then an ASC CDL Effect
+	old := 0.9
+	sourceRange := opentime.NewTimeRange(opentime.NewRationalTime(0, 24), opentime.NewRationalTime(24, 24))
+	effect := gotio.NewEffect("ASC CDL", CDLEffectName, gotio.AnyDictionary{cdlEffectKey: &CDLData{ASCSat: &old}})
+	both := gotio.NewClip("Both", nil, &sourceRange, gotio.AnyDictionary{DefaultCDLMetadataKey: &CDLData{ASCSat: &old}}, []gotio.Effect{effect}, nil, "", nil)
+	effectOnly := gotio.NewClip("Effect", nil, &sourceRange, gotio.AnyDictionary{}, []gotio.Effect{
+		gotio.NewEffect("ASC CDL", CDLEffectName, gotio.AnyDictionary{cdlEffectKey: &CDLData{ASCSat: &old}}),
+	}, nil, "", nil)
+	timeline := gotio.NewTimeline("CDL Order", nil, nil)
+	track := gotio.NewTrack("Video", nil, gotio.TrackKindVideo, nil, nil)
+	track.AppendChild(both)
+	track.AppendChild(effectOnly)
+	timeline.Tracks().AppendChild(track)
+
+	sat := 0.5
+	grades := []*CDLData{{ID: "Both", ASCSat: &sat}, {ID: "Effect", ASCSat: &sat}}
+	if result := NewCDLImporter().ApplyToTimeline(timeline, grades); result.Matched != 2 {
+		t.Fatalf("Result = %+v, want 2 matches", result)
+	}
+	for _, clip := range []*gotio.Clip{both, effectOnly} {
+		if cdl := clipCDL(clip, DefaultCDLMetadataKey); cdl == nil || *cdl.ASCSat != sat {
+			t.Errorf("Clip %s grade = %+v, want the imported grade", clip.Name(), cdl)
+		}
+	}
+	if _, ok := effectOnly.Metadata()[DefaultCDLMetadataKey]; ok {
+		t.Error("Grade of an effect-only clip was added to its metadata")
+	}
+}
@@ -0,0 +1,342 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package ale
+
+import (
+	"path"
+	"strings"
+	"unicode"
+
+	"github.com/Avalanche-io/gotio"
+)
+
+// Color pipeline columns
+const (
+	ColumnColorSpace          = "Color Space"
+	ColumnLUT                 = "LUT"
+	ColumnColorTransformation = "Color Transformation"
+)
+
+// colorColumns lists the color pipeline columns
+var colorColumns = []string{ColumnColorSpace, ColumnLUT, ColumnColorTransformation}
+
+// Color encodings
+const (
+	ColorEncodingYCbCr = "ycbcr"
+	ColorEncodingRGB   = "rgb"
+)
+
+// Signal ranges
+const (
+	ColorRangeLegal = "legal"
+	ColorRangeFull  = "full"
+)
+
+// ColorSpaceName relates an Avid color space spelling to its ACES/OCIO
+// color space name. Aliases are other spellings read as the same space.
+type ColorSpaceName struct {
+	Avid    string
+	OCIO    string
+	Aliases []string
+}
+
+// ColorSpaceNames maps Avid color spaces to ACES/OCIO names. Spellings
+// are matched ignoring case, spaces and punctuation. It may be extended
+// before decoding or encoding.
+var ColorSpaceNames = []ColorSpaceName{
+	{Avid: "REC709", OCIO: "Rec.1886 Rec.709 - Display", Aliases: []string{"BT.709", "709"}},
+	{Avid: "REC2020", OCIO: "Rec.1886 Rec.2020 - Display", Aliases: []string{"BT.2020"}},
+	{Avid: "REC2100 PQ", OCIO: "Rec.2100-PQ - Display", Aliases: []string{"PQ", "ST2084"}},
+	{Avid: "REC2100 HLG", OCIO: "Rec.2100-HLG - Display", Aliases: []string{"HLG"}},
+	{Avid: "DCI-P3", OCIO: "P3-DCI - Display"},
+	{Avid: "P3-D65", OCIO: "P3-D65 - Display"},
+	{Avid: "sRGB", OCIO: "sRGB - Display"},
+	{Avid: "ACES", OCIO: "ACES2065-1", Aliases: []string{"AP0"}},
+	{Avid: "ACEScg", OCIO: "ACEScg"},
+	{Avid: "ACEScct", OCIO: "ACEScct"},
+	{Avid: "ARRI LogC", OCIO: "ARRI LogC3 (EI800)", Aliases: []string{"LogC", "LogC3"}},
+	{Avid: "ARRI LogC4", OCIO: "ARRI LogC4", Aliases: []string{"LogC4"}},
+	{Avid: "Sony S-Log3/S-Gamut3", OCIO: "S-Log3 S-Gamut3", Aliases: []string{"S-Log3"}},
+	{Avid: "Sony S-Log3/S-Gamut3.Cine", OCIO: "S-Log3 S-Gamut3.Cine"},
+	{Avid: "Panasonic V-Log/V-Gamut", OCIO: "V-Log V-Gamut", Aliases: []string{"V-Log"}},
+	{Avid: "RED Log3G10/REDWideGamutRGB", OCIO: "Log3G10 REDWideGamutRGB", Aliases: []string{"Log3G10"}},
+	{Avid: "Canon Log 2/Cinema Gamut", OCIO: "Canon Log 2 CinemaGamut D55", Aliases: []string{"Canon Log 2", "C-Log2"}},
+}
+
+// lutExtensions are the file extensions read as LUT paths
+var lutExtensions = map[string]bool{
+	".cube": true, ".3dl": true, ".lut": true, ".csp": true,
+	".ilut": true, ".olut": true, ".clf": true, ".ctf": true,
+}
+
+// ColorPipeline describes how a clip is viewed, decoded from the Color
+// Space, LUT and Color Transformation columns
+type ColorPipeline struct {
+	Encoding   string   `json:"encoding,omitempty"`
+	ColorSpace string   `json:"colorspace,omitempty"`
+	OCIO       string   `json:"ocio,omitempty"`
+	Range      string   `json:"range,omitempty"`
+	LUT        string   `json:"lut,omitempty"`
+	LUTPath    string   `json:"lut_path,omitempty"`
+	Transforms []string `json:"transforms,omitempty"`
+}
+
+// normalizeColorName folds a color space spelling for matching
+func normalizeColorName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+}
+
+// lookupColorSpace finds a color space by its Avid name, OCIO name or an
+// alias
+func lookupColorSpace(name string) (ColorSpaceName, bool) {
+	key := normalizeColorName(name)
+	if key == "" {
+		return ColorSpaceName{}, false
+	}
+	for _, cs := range ColorSpaceNames {
+		if normalizeColorName(cs.Avid) == key || normalizeColorName(cs.OCIO) == key {
+			return cs, true
+		}
+		for _, alias := range cs.Aliases {
+			if normalizeColorName(alias) == key {
+				return cs, true
+			}
+		}
+	}
+	return ColorSpaceName{}, false
+}
+
+// OCIOColorSpace returns the ACES/OCIO name of an Avid color space
+func OCIOColorSpace(avid string) (string, bool) {
+	cs, ok := lookupColorSpace(avid)
+	return cs.OCIO, ok
+}
+
+// AvidColorSpace returns the Avid spelling of an ACES/OCIO color space
+func AvidColorSpace(ocio string) (string, bool) {
+	cs, ok := lookupColorSpace(ocio)
+	return cs.Avid, ok
+}
+
+// parseColorSpace splits a Color Space value such as
+// "YCbCr REC709 [video levels]" into its encoding, color space and range
+func parseColorSpace(value string, pipeline *ColorPipeline) {
+	value = strings.TrimSpace(value)
+	if open := strings.IndexByte(value, '['); open >= 0 {
+		if end := strings.IndexByte(value[open:], ']'); end > 0 {
+			pipeline.Range = parseColorRange(value[open+1 : open+end])
+			value = strings.TrimSpace(value[:open] + value[open+end+1:])
+		}
+	}
+
+	if first, rest, _ := strings.Cut(value, " "); strings.EqualFold(first, "YCbCr") || strings.EqualFold(first, "RGB") {
+		pipeline.Encoding = strings.ToLower(first)
+		value = strings.TrimSpace(rest)
+	}
+
+	if cs, ok := lookupColorSpace(value); ok {
+		pipeline.ColorSpace, pipeline.OCIO = cs.Avid, cs.OCIO
+	} else {
+		pipeline.ColorSpace = value
+	}
+}
+
+// parseColorRange normalizes a signal range such as "video levels" or
+// "full range". Unknown ranges are kept as written.
+func parseColorRange(value string) string {
+	switch normalizeColorName(value) {
+	case "videolevels", "video", "legal", "legalrange", "limited", "limitedrange", "smpte", "head":
+		return ColorRangeLegal
+	case "fullrange", "full", "datalevels", "data", "extended":
+		return ColorRangeFull
+	}
+	return strings.TrimSpace(value)
+}
+
+// parseLUT reads a LUT value as a file path when it looks like one, naming
+// the LUT after the file
+func parseLUT(value string, pipeline *ColorPipeline) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return
+	}
+	slashed := strings.ReplaceAll(value, `\`, "/")
+	ext := strings.ToLower(path.Ext(slashed))
+	if !strings.Contains(slashed, "/") && !lutExtensions[ext] {
+		pipeline.LUT = value
+		return
+	}
+	pipeline.LUTPath = value
+	pipeline.LUT = strings.TrimSuffix(path.Base(slashed), path.Ext(slashed))
+}
+
+// parseTransforms splits a Color Transformation chain on ">", "|" or ";"
+func parseTransforms(value string) []string {
+	var transforms []string
+	for _, step := range strings.FieldsFunc(value, func(r rune) bool {
+		return r == '>' || r == '|' || r == ';'
+	}) {
+		if step = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(step), "-")); step != "" {
+			transforms = append(transforms, step)
+		}
+	}
+	return transforms
+}
+
+// colorPipeline reads the color pipeline columns through column, or
+// returns nil if none of them has a value
+func colorPipeline(column func(string) string) *ColorPipeline {
+	pipeline := &ColorPipeline{}
+	parseColorSpace(column(ColumnColorSpace), pipeline)
+	parseLUT(column(ColumnLUT), pipeline)
+	pipeline.Transforms = parseTransforms(column(ColumnColorTransformation))
+
+	if pipeline.isZero() {
+		return nil
+	}
+	return pipeline
+}
+
+// isZero reports whether no part of the pipeline was set
+func (p *ColorPipeline) isZero() bool {
+	return p.Encoding == "" && p.ColorSpace == "" && p.OCIO == "" && p.Range == "" &&
+		p.LUT == "" && p.LUTPath == "" && len(p.Transforms) == 0
+}
+
+// colorPipelineFromMap reads a ColorPipeline stored as a map, as it is
+// after a JSON round trip
+func colorPipelineFromMap(m map[string]interface{}) *ColorPipeline {
+	pipeline := &ColorPipeline{
+		Encoding:   stringValue(m["encoding"]),
+		ColorSpace: stringValue(m["colorspace"]),
+		OCIO:       stringValue(m["ocio"]),
+		Range:      stringValue(m["range"]),
+		LUT:        stringValue(m["lut"]),
+		LUTPath:    stringValue(m["lut_path"]),
+	}
+	switch transforms := m["transforms"].(type) {
+	case []string:
+		pipeline.Transforms = transforms
+	case []interface{}:
+		for _, step := range transforms {
+			if s, ok := step.(string); ok {
+				pipeline.Transforms = append(pipeline.Transforms, s)
+			}
+		}
+	}
+	if pipeline.isZero() {
+		return nil
+	}
+	return pipeline
+}
+
+// columnValue formats a color pipeline column in Avid's spelling, or
+// returns false if the pipeline does not set it
+func (p *ColorPipeline) columnValue(column string) (string, bool) {
+	switch column {
+	case ColumnColorSpace:
+		name := p.ColorSpace
+		if cs, ok := lookupColorSpace(name); ok {
+			name = cs.Avid
+		} else if cs, ok := lookupColorSpace(p.OCIO); ok && name == "" {
+			name = cs.Avid
+		}
+		var parts []string
+		switch p.Encoding {
+		case ColorEncodingYCbCr:
+			parts = append(parts, "YCbCr")
+		case ColorEncodingRGB:
+			parts = append(parts, "RGB")
+		}
+		if name != "" {
+			parts = append(parts, name)
+		}
+		switch p.Range {
+		case "":
+		case ColorRangeLegal:
+			parts = append(parts, "[video levels]")
+		case ColorRangeFull:
+			parts = append(parts, "[full range]")
+		default:
+			parts = append(parts, "["+p.Range+"]")
+		}
+		if name == "" {
+			return "", false
+		}
+		return strings.Join(parts, " "), true
+
+	case ColumnLUT:
+		if p.LUTPath != "" {
+			return p.LUTPath, true
+		}
+		if p.LUT != "" {
+			return p.LUT, true
+		}
+
+	case ColumnColorTransformation:
+		if len(p.Transforms) > 0 {
+			return strings.Join(p.Transforms, " > "), true
+		}
+	}
+	return "", false
+}
+
+// clipColorPipeline returns a clip's color pipeline from its color
+// metadata, as a ColorPipeline or a map, falling back to its color columns
+func (e *Encoder) clipColorPipeline(metadata gotio.AnyDictionary, columns map[string]interface{}) *ColorPipeline {
+	switch p := structured(metadata, e.metadataKey, colorField).(type) {
+	case *ColorPipeline:
+		if p != nil {
+			return p
+		}
+	case ColorPipeline:
+		return &p
+	default:
+		if m := asMap(p); m != nil {
+			if pipeline := colorPipelineFromMap(m); pipeline != nil {
+				return pipeline
+			}
+		}
+	}
+
+	return colorPipeline(func(column string) string {
+		if value, ok := columns[column]; ok {
+			return formatCell(ColumnSpec{}, value)
+		}
+		return ""
+	})
+}
+
+// colorValue returns the value of a color pipeline column from a clip's
+// pipeline. A logged value that reads back the same is written as logged.
+func (e *Encoder) colorValue(metadata gotio.AnyDictionary, columns map[string]interface{}, column string) (string, bool) {
+	pipeline := e.clipColorPipeline(metadata, columns)
+	if pipeline == nil {
+		return "", false
+	}
+	value, ok := pipeline.columnValue(column)
+	if !ok {
+		return "", false
+	}
+
+	if raw, ok := columns[column]; ok {
+		logged := formatCell(ColumnSpec{}, raw)
+		if p := colorPipeline(func(c string) string {
+			if c == column {
+				return logged
+			}
+			return ""
+		}); p != nil {
+			if v, ok := p.columnValue(column); ok && v == value {
+				return logged, true
+			}
+		}
+	}
+	return value, true
+}
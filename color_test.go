@@ -0,0 +1,175 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package ale
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/Avalanche-io/gotio"
+	"github.com/Avalanche-io/gotio/opentime"
+)
+
+func TestColorPipeline(t *testing.T) {
+	columns := map[string]string{
+		ColumnColorSpace:          "RGB ARRI LogC [full range]",
+		ColumnLUT:                 `C:\LUTs\Show_LogC_to_709.cube`,
+		ColumnColorTransformation: "LogC to Rec709 > Show Look; Output Trim",
+	}
+	got := colorPipeline(func(column string) string { return columns[column] })
+	want := &ColorPipeline{
+		Encoding:   ColorEncodingRGB,
+		ColorSpace: "ARRI LogC",
+		OCIO:       "ARRI LogC3 (EI800)",
+		Range:      ColorRangeFull,
+		LUT:        "Show_LogC_to_709",
+		LUTPath:    `C:\LUTs\Show_LogC_to_709.cube`,
+		Transforms: []string{"LogC to Rec709", "Show Look", "Output Trim"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("colorPipeline() = %+v, want %+v", got, want)
+	}
+
+	if got := colorPipeline(func(string) string { return "" }); got != nil {
+		t.Errorf("colorPipeline() of empty columns = %+v, want nil", got)
+	}
+}
+
+func TestColorSpaceNames(t *testing.T) {
+	tests := []struct {
+		avid string
+		ocio string
+	}{
+		{"REC709", "Rec.1886 Rec.709 - Display"},
+		{"Sony S-Log3/S-Gamut3.Cine", "S-Log3 S-Gamut3.Cine"},
+		{"ACES", "ACES2065-1"},
+	}
+	for _, tt := range tests {
+		if got, ok := OCIOColorSpace(tt.avid); !ok || got != tt.ocio {
+			t.Errorf("OCIOColorSpace(%q) = %q, %v; want %q", tt.avid, got, ok, tt.ocio)
+		}
+		if got, ok := AvidColorSpace(tt.ocio); !ok || got != tt.avid {
+			t.Errorf("AvidColorSpace(%q) = %q, %v; want %q", tt.ocio, got, ok, tt.avid)
+		}
+	}
+
+	// Spellings match ignoring case and punctuation
+	if got, ok := OCIOColorSpace("bt 709"); !ok || got != "Rec.1886 Rec.709 - Display" {
+		t.Errorf("OCIOColorSpace(bt 709) = %q, %v", got, ok)
+	}
+	if _, ok := OCIOColorSpace("Custom Log"); ok {
+		t.Error("OCIOColorSpace(Custom Log) found a color space")
+	}
+}
+
+func TestDecoder_ColorPipeline(t *testing.T) {
+	file, err := os.Open("testdata/sample.ale")
+	if err != nil {
+		t.Fatalf("Failed to open sample: %v", err)
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		t.Fatalf("Failed to read sample: %v", err)
+	}
+
+	// The pipeline is only decoded on request
+	timeline, err := NewDecoder(bytes.NewReader(data)).Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode ALE: %v", err)
+	}
+	if p := structured(timeline.FindClips(nil, false)[0].Metadata(), DefaultMetadataKey, colorField); p != nil {
+		t.Errorf("Pipeline decoded without WithColorPipeline: %v", p)
+	}
+
+	timeline, err = NewDecoder(bytes.NewReader(data), WithColorPipeline(true)).Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode ALE: %v", err)
+	}
+
+	wantRanges := []string{ColorRangeLegal, ColorRangeFull}
+	clips := timeline.FindClips(nil, false)
+	for i, want := range wantRanges {
+		pipeline, ok := structured(clips[i].Metadata(), DefaultMetadataKey, colorField).(*ColorPipeline)
+		if !ok {
+			t.Fatalf("Clip %d has no color pipeline", i)
+		}
+		if pipeline.Encoding != ColorEncodingYCbCr || pipeline.ColorSpace != "REC709" || pipeline.Range != want {
+			t.Errorf("Clip %d pipeline = %+v, want YCbCr REC709 %s", i, pipeline, want)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).Encode(timeline); err != nil {
+		t.Fatalf("Failed to encode timeline: %v", err)
+	}
+	aleFile, err := NewDecoder(bytes.NewReader(buf.Bytes())).parseALE(t.Context())
+	if err != nil {
+		t.Fatalf("Failed to parse encoded ALE: %v", err)
+	}
+	for i, want := range []string{"YCbCr REC709 [video levels]", "YCbCr REC709 [full range]"} {
+		if got := aleFile.Row(i).Get(ColumnColorSpace); got != want {
+			t.Errorf("Row %d Color Space = %q, want %q", i, got, want)
+		}
+	}
+}
+
+func TestEncoder_ColorPipeline(t *testing.T) {
+	// A pipeline given by OCIO names is written in Avid's spellings
+	pipeline := &ColorPipeline{
+		Encoding:   ColorEncodingRGB,
+		OCIO:       "S-Log3 S-Gamut3.Cine",
+		Range:      ColorRangeLegal,
+		LUT:        "Show LUT",
+		Transforms: []string{"S-Log3 to Rec709", "Show Look"},
+	}
+	data, err := json.Marshal(pipeline)
+	if err != nil {
+		t.Fatalf("Failed to marshal pipeline: %v", err)
+	}
+	var pipelineMap map[string]interface{}
+	if err := json.Unmarshal(data, &pipelineMap); err != nil {
+		t.Fatalf("Failed to unmarshal pipeline: %v", err)
+	}
+
+	for name, value := range map[string]interface{}{"struct": pipeline, "map": pipelineMap} {
+		t.Run(name, func(t *testing.T) {
+			timeline := gotio.NewTimeline("Test Timeline", nil, nil)
+			track := gotio.NewTrack("Video", nil, gotio.TrackKindVideo, nil, nil)
+			sourceRange := opentime.NewTimeRange(
+				opentime.NewRationalTime(0, 24),
+				opentime.NewRationalTime(24, 24),
+			)
+			clip := gotio.NewClip("A001", nil, &sourceRange, gotio.AnyDictionary{DefaultMetadataKey: map[string]interface{}{colorField: value}}, nil, nil, "", nil)
+			track.AppendChild(clip)
+			timeline.Tracks().AppendChild(track)
+
+			var buf bytes.Buffer
+			if err := NewEncoder(&buf).Encode(timeline); err != nil {
+				t.Fatalf("Failed to encode timeline: %v", err)
+			}
+			aleFile, err := NewDecoder(bytes.NewReader(buf.Bytes())).parseALE(t.Context())
+			if err != nil {
+				t.Fatalf("Failed to parse encoded ALE: %v", err)
+			}
+
+			want := map[string]string{
+				ColumnColorSpace:          "RGB Sony S-Log3/S-Gamut3.Cine [video levels]",
+				ColumnLUT:                 "Show LUT",
+				ColumnColorTransformation: "S-Log3 to Rec709 > Show Look",
+			}
+			row := aleFile.Row(0)
+			for col, value := range want {
+				if got := row.Get(col); got != value {
+					t.Errorf("%s = %q, want %q", col, got, value)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package ale
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDecoder_DecodeContextCancelled(t *testing.T) {
+	data, err := os.ReadFile("testdata/sample.ale")
+	if err != nil {
+		t.Fatalf("Failed to read test file: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = NewDecoder(bytes.NewReader(data)).DecodeContext(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("DecodeContext error = %v, want context.Canceled", err)
+	}
+
+	_, err = NewDecoder(bytes.NewReader(data)).DecodeBinContext(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("DecodeBinContext error = %v, want context.Canceled", err)
+	}
+}
+
+func TestDecoder_DecodeContextDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+
+	_, err := NewDecoder(strings.NewReader(syncALE)).DecodeContext(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("DecodeContext error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestEncoder_EncodeContextCancelled(t *testing.T) {
+	timeline, err := NewDecoder(strings.NewReader(syncALE)).DecodeContext(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to decode ALE: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// A cancelled encode leaves the writer untouched
+	var buf bytes.Buffer
+	err = NewEncoder(&buf).EncodeContext(ctx, timeline)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("EncodeContext error = %v, want context.Canceled", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Cancelled encode wrote %d bytes", buf.Len())
+	}
+
+	if err := NewEncoder(&buf).EncodeContext(context.Background(), timeline); err != nil {
+		t.Fatalf("Failed to encode timeline: %v", err)
+	}
+	if !strings.Contains(buf.String(), "A3") {
+		t.Error("Output missing clip A3")
+	}
+}
@@ -0,0 +1,93 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package ale
+
+import (
+	"strings"
+	"time"
+)
+
+// ISO 8601 layouts used for normalized date values
+const (
+	isoDateLayout     = "2006-01-02"
+	isoDateTimeLayout = "2006-01-02T15:04:05"
+)
+
+// Date styles for the encoder. Any Go time layout may be used; layouts
+// without a clock component drop the time of day.
+const (
+	DateStyleISO     = isoDateLayout
+	DateStyleUS      = "01/02/06"
+	DateStyleCompact = "20060102"
+)
+
+// DefaultDateColumns lists the columns parsed as dates by default
+var DefaultDateColumns = []string{
+	ColumnShootDate,
+	ColumnCreationDate,
+	ColumnModifiedDate,
+}
+
+// DefaultDateLayouts lists the month-first layouts tried when parsing dates.
+// Single-digit month and day components also accept two digits.
+var DefaultDateLayouts = []string{
+	"1/2/06 3:04:05 PM",
+	"1/2/2006 3:04:05 PM",
+	"1/2/06 15:04:05",
+	"1/2/2006 15:04:05",
+	"1/2/06",
+	"1/2/2006",
+	"20060102",
+	"2006-01-02",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006/01/02",
+}
+
+// parseDate parses a date string using the given layouts and returns its
+// ISO 8601 form. Date-only values are returned as YYYY-MM-DD, values with
+// a time of day as YYYY-MM-DDTHH:MM:SS.
+func parseDate(s string, layouts []string, dayFirst bool) (string, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return "", false
+	}
+
+	for _, layout := range layouts {
+		if dayFirst {
+			layout = dayFirstLayout(layout)
+		}
+		t, err := time.Parse(layout, s)
+		if err != nil {
+			continue
+		}
+		if hasClock(layout) {
+			return t.Format(isoDateTimeLayout), true
+		}
+		return t.Format(isoDateLayout), true
+	}
+
+	return "", false
+}
+
+// formatDate reformats an ISO 8601 date value with the given layout
+func formatDate(iso, layout string) (string, bool) {
+	for _, isoLayout := range []string{isoDateTimeLayout, isoDateLayout} {
+		if t, err := time.Parse(isoLayout, iso); err == nil {
+			return t.Format(layout), true
+		}
+	}
+	return "", false
+}
+
+// dayFirstLayout swaps the month and day components of a month-first layout
+func dayFirstLayout(layout string) string {
+	return strings.Replace(layout, "1/2/", "2/1/", 1)
+}
+
+// hasClock reports whether a layout includes a time of day
+func hasClock(layout string) bool {
+	// Minutes are the only component every clock layout shares
+	return strings.Contains(layout, "04")
+}
@@ -0,0 +1,120 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package ale
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		dayFirst bool
+		want     string
+		wantOk   bool
+	}{
+		{"US short", "07/21/14", false, "2014-07-21", true},
+		{"US single digits", "7/2/14", false, "2014-07-02", true},
+		{"compact", "20190501", false, "2019-05-01", true},
+		{"ISO", "2019-05-01", false, "2019-05-01", true},
+		{"with time", "7/28/17 10:49:11 AM", false, "2017-07-28T10:49:11", true},
+		{"with afternoon time", "7/25/17 5:39:38 PM", false, "2017-07-25T17:39:38", true},
+		{"day first", "21/07/14", true, "2014-07-21", true},
+		{"day first ambiguous", "02/03/14", true, "2014-03-02", true},
+		{"month first rejects day first", "21/07/14", false, "", false},
+		{"empty", "", false, "", false},
+		{"invalid", "yesterday", false, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseDate(tt.input, DefaultDateLayouts, tt.dayFirst)
+			if ok != tt.wantOk {
+				t.Fatalf("parseDate(%q) ok = %v, want %v", tt.input, ok, tt.wantOk)
+			}
+			if got != tt.want {
+				t.Errorf("parseDate(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecoder_DateColumns(t *testing.T) {
+	tests := []struct {
+		file   string
+		column string
+		want   string
+	}{
+		{"testdata/sample.ale", ColumnCreationDate, "2017-07-28T10:49:11"},
+		{"testdata/sample.ale", ColumnModifiedDate, "2017-07-28T10:49:43"},
+		{"testdata/sample2.ale", ColumnShootDate, "2014-07-21"},
+		{"testdata/sample_cdl.ale", ColumnShootDate, "2019-05-01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file+"/"+tt.column, func(t *testing.T) {
+			data, err := os.ReadFile(tt.file)
+			if err != nil {
+				t.Fatalf("Failed to read test file: %v", err)
+			}
+
+			timeline, err := NewDecoder(bytes.NewReader(data)).Decode()
+			if err != nil {
+				t.Fatalf("Failed to decode ALE: %v", err)
+			}
+
+			metadata := timeline.FindClips(nil, false)[0].Metadata()
+			dates, ok := structured(metadata, "ALE", "_dates").(map[string]interface{})
+			if !ok {
+				t.Fatal("Missing ALE _dates metadata")
+			}
+			if dates[tt.column] != tt.want {
+				t.Errorf("%s = %v, want %s", tt.column, dates[tt.column], tt.want)
+			}
+
+			// The original string is kept for round trip
+			aleMap := metadata["ALE"].(map[string]interface{})
+			if _, ok := aleMap[tt.column]; !ok {
+				t.Errorf("Missing original %s in ALE metadata", tt.column)
+			}
+		})
+	}
+}
+
+func TestEncoder_WithDateStyle(t *testing.T) {
+	aleContent := `Heading
+FIELD_DELIM	TABS
+
+Column
+Name	Duration	Shoot Date
+
+Data
+Clip001	100	07/21/14
+`
+
+	timeline, err := NewDecoder(strings.NewReader(aleContent)).Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode ALE: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).Encode(timeline); err != nil {
+		t.Fatalf("Failed to encode timeline: %v", err)
+	}
+	if !strings.Contains(buf.String(), "07/21/14") {
+		t.Error("Default encoding should keep the original date string")
+	}
+
+	buf.Reset()
+	if err := NewEncoder(&buf, WithDateStyle(DateStyleISO)).Encode(timeline); err != nil {
+		t.Fatalf("Failed to encode timeline: %v", err)
+	}
+	if !strings.Contains(buf.String(), "2014-07-21") {
+		t.Errorf("Output missing ISO shoot date:\n%s", buf.String())
+	}
+}
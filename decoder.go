@@ -5,6 +5,7 @@ package ale
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"io"
 	"strings"
@@ -17,8 +18,34 @@ import (
 type Decoder struct {
 	r              io.Reader
 	fps            float64
-	nameColumnKey  string
+	nameSources    []string
 	dropFrame      bool
+	dateColumns    []string
+	dateLayouts    []string
+	dayFirst       bool
+	schema         *Schema
+	warnings       []error
+	aliases        columnAliases
+	columns        map[string]string
+	metadataKey    string
+	cdlKey         string
+	layout         MetadataLayout
+	grouping       TrackGrouping
+	timeline       TimelineLayout
+	multicam       bool
+	multicamTol    float64
+	binGrouping    TrackGrouping
+	name           string
+	nameFromFile   bool
+	globalStart    bool
+	workers        int
+	stereo         bool
+	stereoStacks   map[*gotio.Clip]*gotio.Stack
+	pulldown       bool
+	varispeed      bool
+	cdlEffect      bool
+	geometry       bool
+	color          bool
 }
 
 // DecoderOption configures a Decoder
@@ -33,8 +60,16 @@ func WithFPS(fps float64) DecoderOption {
 
 // WithNameColumn sets the column name to use for clip names
 func WithNameColumn(key string) DecoderOption {
+	return WithNameSources(key)
+}
+
+// WithNameSources sets an ordered list of clip name sources. Each source is
+// either a column name or a template such as "{Scene}-{Take}{Camera}"
+// built from other columns; the first non-empty result is used, falling
+// back to "Clip N". A template only applies when all its columns are set.
+func WithNameSources(sources ...string) DecoderOption {
 	return func(d *Decoder) {
-		d.nameColumnKey = key
+		d.nameSources = sources
 	}
 }
 
@@ -45,13 +80,205 @@ func WithDropFrame(dropFrame bool) DecoderOption {
 	}
 }
 
+// WithDateColumns sets the columns parsed as dates
+func WithDateColumns(columns ...string) DecoderOption {
+	return func(d *Decoder) {
+		d.dateColumns = columns
+	}
+}
+
+// WithDateLayouts sets the Go time layouts tried when parsing date columns
+func WithDateLayouts(layouts ...string) DecoderOption {
+	return func(d *Decoder) {
+		d.dateLayouts = layouts
+	}
+}
+
+// WithDayFirst sets whether ambiguous dates are read day-first (e.g. 21/07/14)
+func WithDayFirst(dayFirst bool) DecoderOption {
+	return func(d *Decoder) {
+		d.dayFirst = dayFirst
+	}
+}
+
+// WithSchema sets the column schema used to store typed values
+func WithSchema(schema *Schema) DecoderOption {
+	return func(d *Decoder) {
+		d.schema = schema
+	}
+}
+
+// WithColumnAliases adds aliases mapping incoming column names to canonical
+// column names (e.g. "Reel" to "Tape"). Aliases are matched case- and
+// whitespace-insensitively and extend DefaultColumnAliases; mapping a name
+// to "" disables a default alias.
+func WithColumnAliases(aliases map[string]string) DecoderOption {
+	return func(d *Decoder) {
+		d.aliases = newColumnAliases(aliases)
+	}
+}
+
+// WithMetadataKey sets the clip metadata key decoded columns are stored under
+func WithMetadataKey(key string) DecoderOption {
+	return func(d *Decoder) {
+		d.metadataKey = key
+	}
+}
+
+// WithCDLMetadataKey sets the clip metadata key CDL data is stored under
+func WithCDLMetadataKey(key string) DecoderOption {
+	return func(d *Decoder) {
+		d.cdlKey = key
+	}
+}
+
+// WithMetadataLayout sets how decoded columns are arranged under the
+// metadata key, either flat or nested by category
+func WithMetadataLayout(layout MetadataLayout) DecoderOption {
+	return func(d *Decoder) {
+		d.layout = layout
+	}
+}
+
+// WithTrackGrouping sets how clips are grouped onto tracks, e.g.
+// GroupByCamera or GroupByColumn("Labroll")
+func WithTrackGrouping(grouping TrackGrouping) DecoderOption {
+	return func(d *Decoder) {
+		d.grouping = grouping
+	}
+}
+
+// WithTrackKeyFunc groups clips onto tracks with a function returning each
+// row's track key and kind
+func WithTrackKeyFunc(fn TrackKeyFunc) DecoderOption {
+	return WithTrackGrouping(TrackGrouping{Key: fn})
+}
+
+// WithTimelineLayout sets how clips are placed on their tracks, either
+// back-to-back in row order (TimelineSequential, the default) or at their
+// Start timecode (TimelineTimecodeSync)
+func WithTimelineLayout(layout TimelineLayout) DecoderOption {
+	return func(d *Decoder) {
+		d.timeline = layout
+	}
+}
+
+// WithMulticamGroups groups rows whose timecode ranges overlap, within a
+// tolerance in frames, into multicam groups with one track per camera
+// (from the Camera or Camroll column). Each group becomes a Stack on a
+// single "Multicam" track.
+func WithMulticamGroups(toleranceFrames float64) DecoderOption {
+	return func(d *Decoder) {
+		d.multicam = true
+		d.multicamTol = toleranceFrames
+	}
+}
+
+// WithBinGrouping nests the clips returned by DecodeBin in one collection
+// per group key, e.g. GroupByShootDate or GroupByColumn("Camroll")
+func WithBinGrouping(grouping TrackGrouping) DecoderOption {
+	return func(d *Decoder) {
+		d.binGrouping = grouping
+	}
+}
+
+// WithTimelineName sets the name of the decoded timeline or bin
+func WithTimelineName(name string) DecoderOption {
+	return func(d *Decoder) {
+		d.name = name
+	}
+}
+
+// WithTimelineNameFromFile names the decoded timeline or bin after the
+// file being read, without its extension. The reader must have a Name
+// method, as *os.File does.
+func WithTimelineNameFromFile() DecoderOption {
+	return func(d *Decoder) {
+		d.nameFromFile = true
+	}
+}
+
+// WithGlobalStartTime sets the timeline's global start time to the
+// earliest clip Start timecode
+func WithGlobalStartTime(enabled bool) DecoderOption {
+	return func(d *Decoder) {
+		d.globalStart = enabled
+	}
+}
+
+// WithWorkers converts rows to clips on a pool of n goroutines. Clips and
+// warnings keep their row order. Values below 2 convert rows serially.
+func WithWorkers(n int) DecoderOption {
+	return func(d *Decoder) {
+		d.workers = n
+	}
+}
+
+// WithStereoPairs pairs left- and right-eye rows sharing an S3D Group Name
+// (or S3D Clip Name) into a Stack holding both eyes, and stores the S3D
+// columns as structured metadata under the metadata key
+func WithStereoPairs(enabled bool) DecoderOption {
+	return func(d *Decoder) {
+		d.stereo = enabled
+	}
+}
+
+// WithPulldown reads the Start, End and Duration of rows with a Pullin
+// phase as 29.97 video timecode with 2:3 pulldown and converts them to
+// exact 23.976 film frames. Pullout values that do not match the cadence
+// and cadences other than 2:3 are reported by Warnings.
+func WithPulldown(enabled bool) DecoderOption {
+	return func(d *Decoder) {
+		d.pulldown = enabled
+	}
+}
+
+// WithVarispeed attaches a LinearTimeWarp to rows whose capture rate
+// (CFPS) differs from the project rate, with a time scalar of FPS / CFPS
+func WithVarispeed(enabled bool) DecoderOption {
+	return func(d *Decoder) {
+		d.varispeed = enabled
+	}
+}
+
+// WithImageGeometry decodes the image geometry columns into an
+// ImageGeometry stored under the metadata key
+func WithImageGeometry(enabled bool) DecoderOption {
+	return func(d *Decoder) {
+		d.geometry = enabled
+	}
+}
+
+// WithColorPipeline decodes the Color Space, LUT and Color Transformation
+// columns into a ColorPipeline stored under the metadata key
+func WithColorPipeline(enabled bool) DecoderOption {
+	return func(d *Decoder) {
+		d.color = enabled
+	}
+}
+
+// WithCDLEffect attaches grades to clips as an ASC CDL Effect instead of
+// storing them under the CDL metadata key. The effect is specific to this
+// package; other OTIO tools read grades from metadata["cdl"].
+func WithCDLEffect(enabled bool) DecoderOption {
+	return func(d *Decoder) {
+		d.cdlEffect = enabled
+	}
+}
+
 // NewDecoder creates a new ALE decoder
 func NewDecoder(r io.Reader, opts ...DecoderOption) *Decoder {
 	d := &Decoder{
 		r:             r,
 		fps:           DefaultFPS,
-		nameColumnKey: ColumnName,
+		nameSources:   []string{ColumnName},
 		dropFrame:     false,
+		dateColumns:   DefaultDateColumns,
+		dateLayouts:   DefaultDateLayouts,
+		aliases:       newColumnAliases(nil),
+		metadataKey:   DefaultMetadataKey,
+		cdlKey:        DefaultCDLMetadataKey,
+		layout:        LayoutFlat,
 	}
 	for _, opt := range opts {
 		opt(d)
@@ -61,7 +288,25 @@ func NewDecoder(r io.Reader, opts ...DecoderOption) *Decoder {
 
 // Decode parses an ALE file and returns an OTIO Timeline
 func (d *Decoder) Decode() (*gotio.Timeline, error) {
-	aleFile, err := d.parseALE()
+	return d.DecodeContext(context.Background())
+}
+
+// DecodeContext is like Decode but stops between lines and rows once ctx
+// is done, returning an error wrapping ctx.Err()
+func (d *Decoder) DecodeContext(ctx context.Context) (*gotio.Timeline, error) {
+	aleFile, err := d.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.aleToTimeline(ctx, aleFile)
+}
+
+// read parses the ALE file and applies its FPS header
+func (d *Decoder) read(ctx context.Context) (*ALEFile, error) {
+	d.warnings = nil
+
+	aleFile, err := d.parseALE(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse ALE: %w", err)
 	}
@@ -74,11 +319,18 @@ func (d *Decoder) Decode() (*gotio.Timeline, error) {
 		}
 	}
 
-	return d.aleToTimeline(aleFile)
+	return aleFile, nil
+}
+
+// Warnings returns the problems found during the last Decode that did not
+// stop decoding, such as *CellError values for cells that do not match
+// their declared column type
+func (d *Decoder) Warnings() []error {
+	return d.warnings
 }
 
 // parseALE reads and parses the ALE file structure
-func (d *Decoder) parseALE() (*ALEFile, error) {
+func (d *Decoder) parseALE(ctx context.Context) (*ALEFile, error) {
 	aleFile := NewALEFile()
 	scanner := bufio.NewScanner(d.r)
 
@@ -87,7 +339,11 @@ func (d *Decoder) parseALE() (*ALEFile, error) {
 	inData := false
 	var columns []string
 
-	for scanner.Scan() {
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("cancelled at line %d: %w", lineNum, err)
+		}
+
 		line := scanner.Text()
 		trimmed := strings.TrimSpace(line)
 
@@ -112,7 +368,7 @@ func (d *Decoder) parseALE() (*ALEFile, error) {
 				for i, col := range columns {
 					columns[i] = strings.TrimSpace(col)
 				}
-				aleFile.Columns = columns
+				aleFile.SetColumns(columns)
 			}
 			continue
 		}
@@ -124,29 +380,30 @@ func (d *Decoder) parseALE() (*ALEFile, error) {
 
 		// Parse heading section
 		if inHeading {
+			// Some writers put several key/value pairs on one line
 			parts := splitTabs(line)
-			if len(parts) >= 2 {
-				key := strings.TrimSpace(parts[0])
-				value := strings.TrimSpace(parts[1])
-				aleFile.Headers[key] = value
+			for i := 0; i+1 < len(parts); i += 2 {
+				key := strings.TrimSpace(parts[i])
+				if key == "" {
+					continue
+				}
+				aleFile.Headers[key] = strings.TrimSpace(parts[i+1])
 			}
 			continue
 		}
 
 		// Parse data rows
 		if inData && len(columns) > 0 {
-			values := splitTabs(line)
-			if len(values) > 0 {
-				row := make(map[string]string)
-				for i, col := range columns {
-					if i < len(values) {
-						row[col] = strings.TrimSpace(values[i])
-					} else {
-						row[col] = ""
-					}
-				}
-				aleFile.Rows = append(aleFile.Rows, row)
+			// Pad short rows and drop values past the last column
+			row := splitTabs(line)
+			if len(row) < len(columns) {
+				row = append(row, make([]string, len(columns)-len(row))...)
 			}
+			row = row[:len(columns):len(columns)]
+			for i, value := range row {
+				row[i] = strings.TrimSpace(value)
+			}
+			aleFile.Rows = append(aleFile.Rows, row)
 		}
 	}
 
@@ -158,213 +415,248 @@ func (d *Decoder) parseALE() (*ALEFile, error) {
 }
 
 // aleToTimeline converts an ALEFile structure to an OTIO Timeline
-func (d *Decoder) aleToTimeline(aleFile *ALEFile) (*gotio.Timeline, error) {
-	if len(aleFile.Rows) == 0 {
-		return nil, fmt.Errorf("no data rows in ALE file")
+func (d *Decoder) aleToTimeline(ctx context.Context, aleFile *ALEFile) (*gotio.Timeline, error) {
+	clips, rows, err := d.decodeRows(ctx, aleFile)
+	if err != nil {
+		return nil, err
+	}
+
+	origin := earliestStart(clips, d.fps)
+
+	var globalStart *opentime.RationalTime
+	if d.globalStart && hasSourceRange(clips) {
+		start := opentime.NewRationalTime(origin, d.fps)
+		globalStart = &start
 	}
 
 	// Create timeline
 	timeline := gotio.NewTimeline(
-		"ALE Timeline",
-		nil,
-		nil,
+		d.timelineName(DefaultTimelineName),
+		globalStart,
+		d.headingMetadata(aleFile),
 	)
 
-	// Check if we have a Tracks column to determine track types
-	hasTracksColumn := false
-	for _, col := range aleFile.Columns {
-		if col == ColumnTracks {
-			hasTracksColumn = true
-			break
+	// Multicam groups replace the per-key tracks
+	if d.multicam {
+		cameras := make([]string, len(rows))
+		for i, row := range rows {
+			cameras[i] = multicamCamera(row)
+		}
+		track, err := d.multicamTrack(clips, cameras, origin)
+		if err != nil {
+			return nil, err
 		}
+		if err := timeline.Tracks().AppendChild(track); err != nil {
+			return nil, fmt.Errorf("failed to add track to timeline: %w", err)
+		}
+		return timeline, nil
 	}
 
-	if hasTracksColumn {
-		// Parse tracks from Tracks column - group clips by track type
-		trackMap := make(map[string]*gotio.Track)
-
-		for i, row := range aleFile.Rows {
-			clip, err := d.rowToClip(row, i)
-			if err != nil {
-				return nil, fmt.Errorf("failed to convert row %d to clip: %w", i, err)
-			}
-			if clip == nil {
-				continue
-			}
-
-			// Get track info from Tracks column
-			tracksValue := row[ColumnTracks]
-			trackKind := d.parseTrackKind(tracksValue)
-
-			// Create track key (e.g., "V1", "A1", "VA1")
-			trackKey := tracksValue
-			if trackKey == "" {
-				trackKey = "V" // Default to video
-			}
-
-			// Get or create track
-			track, exists := trackMap[trackKey]
-			if !exists {
-				trackName := trackKey
-				track = gotio.NewTrack(
-					trackName,
-					nil,
-					trackKind,
-					nil,
-					nil,
-				)
-				trackMap[trackKey] = track
-			}
+	// Group clips onto tracks
+	grouping := d.trackGrouping()
+	groups := make(map[string]*trackGroup)
+	kinds := make(map[string]string)
+	var trackKeys []string
 
-			if err := track.AppendChild(clip); err != nil {
-				return nil, fmt.Errorf("failed to append clip to track: %w", err)
-			}
+	for i, clip := range clips {
+		trackKey, trackKind := grouping.Key(rows[i])
+		if trackKey == "" {
+			trackKey = trackKind
 		}
 
-		// Add all tracks to timeline in a consistent order
-		// Sort track keys for consistent output
-		var trackKeys []string
-		for key := range trackMap {
-			trackKeys = append(trackKeys, key)
+		// Get or create track group
+		group, exists := groups[trackKey]
+		if !exists {
+			group = &trackGroup{kind: trackKind}
+			groups[trackKey] = group
+			kinds[trackKey] = trackKind
+			trackKeys = append(trackKeys, trackKey)
 		}
-		// Sort: V before A, then by number
-		sortTrackKeys(trackKeys)
+		group.clips = append(group.clips, clip)
+	}
 
-		for _, key := range trackKeys {
-			if err := timeline.Tracks().AppendChild(trackMap[key]); err != nil {
-				return nil, fmt.Errorf("failed to add track to timeline: %w", err)
-			}
-		}
-	} else {
-		// No Tracks column - create a single video track like before
-		videoTrack := gotio.NewTrack(
-			"Video",
-			nil,
-			gotio.TrackKindVideo,
-			nil,
-			nil,
-		)
+	// Add all tracks to timeline in a consistent order
+	grouping.sort(trackKeys, kinds)
 
-		for i, row := range aleFile.Rows {
-			clip, err := d.rowToClip(row, i)
-			if err != nil {
-				return nil, fmt.Errorf("failed to convert row %d to clip: %w", i, err)
-			}
-			if clip != nil {
-				if err := videoTrack.AppendChild(clip); err != nil {
-					return nil, fmt.Errorf("failed to append clip to track: %w", err)
-				}
-			}
+	for _, key := range trackKeys {
+		tracks, err := d.layoutTracks(key, groups[key], origin)
+		if err != nil {
+			return nil, err
 		}
-
-		if err := timeline.Tracks().AppendChild(videoTrack); err != nil {
-			return nil, fmt.Errorf("failed to add track to timeline: %w", err)
+		for _, track := range tracks {
+			if err := timeline.Tracks().AppendChild(track); err != nil {
+				return nil, fmt.Errorf("failed to add track to timeline: %w", err)
+			}
 		}
 	}
 
 	return timeline, nil
 }
 
-// parseTrackKind determines the track kind from a Tracks column value
-func (d *Decoder) parseTrackKind(tracksValue string) string {
-	// Tracks column can be: V, A, VA, V1, A1, VA1, etc.
-	tracksValue = strings.TrimSpace(strings.ToUpper(tracksValue))
+// decodeRows converts the data rows to clips, returning each clip with
+// the row it was decoded from
+func (d *Decoder) decodeRows(ctx context.Context, aleFile *ALEFile) ([]*gotio.Clip, []Row, error) {
+	if len(aleFile.Rows) == 0 {
+		return nil, nil, fmt.Errorf("no data rows in ALE file")
+	}
+
+	// Map canonical columns to the names used in this file
+	d.resolveColumns(aleFile.Columns)
 
-	if strings.Contains(tracksValue, "V") && strings.Contains(tracksValue, "A") {
-		// Both video and audio - use video for primary
-		return gotio.TrackKindVideo
-	} else if strings.Contains(tracksValue, "A") {
-		return gotio.TrackKindAudio
+	clips, rows, err := d.convertRows(ctx, aleFile)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	// Default to video
-	return gotio.TrackKindVideo
+	if d.stereo {
+		return d.pairStereo(clips, rows)
+	}
+	return clips, rows, nil
 }
 
-// sortTrackKeys sorts track keys: V before A, then by number
-func sortTrackKeys(keys []string) {
-	// Simple bubble sort for track keys
-	for i := 0; i < len(keys); i++ {
-		for j := i + 1; j < len(keys); j++ {
-			if compareTrackKeys(keys[i], keys[j]) > 0 {
-				keys[i], keys[j] = keys[j], keys[i]
-			}
+// convertRows converts each data row to a clip, serially or on the worker
+// pool
+func (d *Decoder) convertRows(ctx context.Context, aleFile *ALEFile) ([]*gotio.Clip, []Row, error) {
+	if d.workers > 1 {
+		return d.convertRowsParallel(ctx, aleFile)
+	}
+
+	var clips []*gotio.Clip
+	var rows []Row
+	for i := range aleFile.Rows {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, fmt.Errorf("decode cancelled at row %d: %w", i, err)
+		}
+
+		row := d.row(aleFile, i)
+		clip, warnings, err := d.rowToClip(row, i)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to convert row %d to clip: %w", i, err)
+		}
+		d.warnings = append(d.warnings, warnings...)
+		if clip == nil {
+			continue
 		}
+		clips = append(clips, clip)
+		rows = append(rows, row)
 	}
+
+	return clips, rows, nil
 }
 
-// compareTrackKeys compares two track keys for sorting
-func compareTrackKeys(a, b string) int {
-	// V comes before A, VA comes before both
-	aHasV := strings.Contains(a, "V")
-	aHasA := strings.Contains(a, "A")
-	bHasV := strings.Contains(b, "V")
-	bHasA := strings.Contains(b, "A")
+// resolveColumns maps each canonical column to the file column that
+// provides it, honoring an explicit name column
+func (d *Decoder) resolveColumns(columns []string) {
+	d.columns = d.aliases.resolve(columns)
 
-	// Both have V and A
-	if aHasV && aHasA && bHasV && bHasA {
-		return strings.Compare(a, b)
+	// A leading plain column overrides the resolved name column
+	if len(d.nameSources) > 0 {
+		primary := d.nameSources[0]
+		if !isNameTemplate(primary) && primary != ColumnName {
+			d.columns[ColumnName] = primary
+		}
 	}
+}
 
-	// Only a has both
-	if aHasV && aHasA {
-		return -1
+// clipName returns the first non-empty name from the name sources
+func (d *Decoder) clipName(row Row) string {
+	for i, source := range d.nameSources {
+		if isNameTemplate(source) {
+			if name, ok := expandNameTemplate(source, row.value); ok {
+				return name
+			}
+			continue
+		}
+
+		// The primary source goes through column resolution
+		col := source
+		if i == 0 {
+			col = d.column(ColumnName)
+		}
+		if name := row.value(col); name != "" {
+			return name
+		}
 	}
+	return ""
+}
 
-	// Only b has both
-	if bHasV && bHasA {
-		return 1
+// column returns the file column providing a canonical column
+func (d *Decoder) column(canonical string) string {
+	if col, ok := d.columns[canonical]; ok {
+		return col
 	}
+	return canonical
+}
 
-	// V before A
-	if aHasV && bHasA {
-		return -1
+// trackGrouping returns the configured track grouping, defaulting to the
+// Tracks column when present and a single video track otherwise
+func (d *Decoder) trackGrouping() TrackGrouping {
+	if d.grouping.Key != nil {
+		return d.grouping
 	}
-	if aHasA && bHasV {
-		return 1
+	if _, ok := d.columns[ColumnTracks]; ok {
+		return GroupByTracks
 	}
+	return singleTrack
+}
 
-	// Both same type, compare lexically
-	return strings.Compare(a, b)
+// row returns a view of data row i that resolves canonical column names
+func (d *Decoder) row(aleFile *ALEFile, i int) Row {
+	row := aleFile.Row(i)
+	row.columns = d.columns
+	return row
 }
 
-// rowToClip converts an ALE row to an OTIO Clip
-func (d *Decoder) rowToClip(row map[string]string, index int) (*gotio.Clip, error) {
+// rowToClip converts an ALE row to an OTIO Clip, returning the problems
+// found in the row that do not stop decoding. It only reads decoder state,
+// so rows may be converted concurrently.
+func (d *Decoder) rowToClip(row Row, index int) (*gotio.Clip, []error, error) {
 	// Get clip name
-	name := row[d.nameColumnKey]
+	name := d.clipName(row)
 	if name == "" {
 		name = fmt.Sprintf("Clip %d", index+1)
 	}
 
 	// Parse timecodes
 	var sourceRange *opentime.TimeRange
-	startTC := row[ColumnStart]
-	endTC := row[ColumnEnd]
-	durationStr := row[ColumnDuration]
+	startTC := row.value(d.column(ColumnStart))
+	endTC := row.value(d.column(ColumnEnd))
+	durationStr := row.value(d.column(ColumnDuration))
+
+	// Rows with a pull-in phase log 29.97 video timecode of 23.976 film
+	var pulldownInfo map[string]interface{}
+	var warnings []error
+	if d.pulldown {
+		var err error
+		sourceRange, pulldownInfo, warnings, err = d.pulldownRange(row, index)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
 
-	if startTC != "" && endTC != "" {
+	if sourceRange == nil && startTC != "" && endTC != "" {
 		// Parse start and end timecodes
 		startTime, err := parseTimecode(startTC, d.fps)
 		if err != nil {
-			return nil, fmt.Errorf("invalid start timecode '%s': %w", startTC, err)
+			return nil, nil, fmt.Errorf("invalid start timecode '%s': %w", startTC, err)
 		}
 
 		endTime, err := parseTimecode(endTC, d.fps)
 		if err != nil {
-			return nil, fmt.Errorf("invalid end timecode '%s': %w", endTC, err)
+			return nil, nil, fmt.Errorf("invalid end timecode '%s': %w", endTC, err)
 		}
 
 		duration := opentime.DurationFromStartEndTime(startTime, endTime)
 		sourceRange = &opentime.TimeRange{}
 		*sourceRange = opentime.NewTimeRange(startTime, duration)
-	} else if durationStr != "" {
+	} else if sourceRange == nil && durationStr != "" {
 		// Parse duration
 		duration, err := parseFrameNumber(durationStr, d.fps)
 		if err != nil {
 			// Try parsing as timecode
 			duration, err = parseTimecode(durationStr, d.fps)
 			if err != nil {
-				return nil, fmt.Errorf("invalid duration '%s': %w", durationStr, err)
+				return nil, nil, fmt.Errorf("invalid duration '%s': %w", durationStr, err)
 			}
 		}
 
@@ -372,7 +664,7 @@ func (d *Decoder) rowToClip(row map[string]string, index int) (*gotio.Clip, erro
 		if startTC != "" {
 			startTime, err = parseTimecode(startTC, d.fps)
 			if err != nil {
-				return nil, fmt.Errorf("invalid start timecode '%s': %w", startTC, err)
+				return nil, nil, fmt.Errorf("invalid start timecode '%s': %w", startTC, err)
 			}
 		}
 		sourceRange = &opentime.TimeRange{}
@@ -381,9 +673,11 @@ func (d *Decoder) rowToClip(row map[string]string, index int) (*gotio.Clip, erro
 
 	// Create media reference
 	var mediaRef gotio.MediaReference
-	sourceFile := row[ColumnSourceFile]
+	sourceColumn := d.column(ColumnSourceFile)
+	sourceFile := row.value(sourceColumn)
 	if sourceFile == "" {
-		sourceFile = row[ColumnTape]
+		sourceColumn = d.column(ColumnTape)
+		sourceFile = row.value(sourceColumn)
 	}
 
 	if sourceFile != "" {
@@ -401,43 +695,143 @@ func (d *Decoder) rowToClip(row map[string]string, index int) (*gotio.Clip, erro
 		)
 	}
 
-	// Create clip metadata - preserve ALL columns dynamically in metadata["ALE"]
+	// Create clip metadata - preserve ALL columns dynamically under the metadata key
 	metadata := make(gotio.AnyDictionary)
 	aleMetadata := make(map[string]interface{})
 
-	// Parse ASC CDL data first if present
-	ascSOP := row["ASC_SOP"]
-	ascSat := row["ASC_SAT"]
-	if ascSOP != "" || ascSat != "" {
-		cdl, err := parseASCCDL(ascSOP, ascSat)
-		if err == nil && cdl != nil {
-			metadata["cdl"] = cdl
-		}
+	// Parse ASC CDL data first if present, with its ColorCorrection id
+	// and descriptions
+	cdl, cdlColumns, cdlWarnings := rowCDL(row, index)
+	if cdl != nil && !d.cdlEffect {
+		metadata[d.cdlKey] = cdl
 	}
+	warnings = append(warnings, cdlWarnings...)
 
 	// Columns to exclude from ALE metadata (these are handled specially)
 	// We only exclude the core OTIO fields that map directly to clip properties
 	excludeColumns := map[string]bool{
-		d.nameColumnKey:  true, // Mapped to clip.Name
-		ColumnStart:      true, // Mapped to sourceRange.StartTime
-		ColumnEnd:        true, // Mapped to sourceRange.EndTime
-		ColumnDuration:   true, // Mapped to sourceRange.Duration
-		ColumnSourceFile: true, // Mapped to mediaReference
-		ColumnTape:       true, // Fallback for mediaReference
-		"ASC_SOP":        true, // Parsed into metadata["cdl"]
-		"ASC_SAT":        true, // Parsed into metadata["cdl"]
+		d.column(ColumnName):       true, // Mapped to clip.Name
+		d.column(ColumnStart):      true, // Mapped to sourceRange.StartTime
+		d.column(ColumnEnd):        true, // Mapped to sourceRange.EndTime
+		d.column(ColumnDuration):   true, // Mapped to sourceRange.Duration
+		sourceColumn:               true, // Mapped to mediaReference
+	}
+	for _, col := range cdlColumns {
+		excludeColumns[d.column(col)] = true // Parsed into CDL metadata
 	}
 
 	// Store all remaining columns in ALE metadata for round-trip preservation
-	for key, value := range row {
-		if !excludeColumns[key] && value != "" {
+	raw := make(map[string]interface{})
+	for key, value := range row.All() {
+		if excludeColumns[key] || value == "" || (d.stereo && isStereoColumn(key)) {
+			continue
+		}
+
+		// Malformed ASC_SOP and ASC_SAT cells were reported by rowCDL
+		spec, ok := d.schema.Lookup(key)
+		if !ok || key == ColumnASCSOP || key == ColumnASCSat {
 			aleMetadata[key] = value
+			continue
+		}
+
+		// Store the typed value, keeping the raw string when it is invalid
+		typed, err := d.parseCell(spec, value)
+		if err != nil {
+			warnings = append(warnings, &CellError{
+				Row:    index,
+				Column: key,
+				Value:  value,
+				Type:   spec.Type,
+				Err:    err,
+			})
+			typed = value
+		}
+		aleMetadata[key] = typed
+
+		// Keep the logged spelling of values that format differently
+		if formatCell(spec, typed) != value {
+			raw[key] = value
 		}
 	}
 
 	// Only add ALE metadata if we have any
 	if len(aleMetadata) > 0 {
-		metadata["ALE"] = aleMetadata
+		metadata[d.metadataKey] = layoutColumns(aleMetadata, d.layout)
+	}
+	if len(raw) > 0 {
+		setStructured(metadata, d.metadataKey, rawField, raw)
+	}
+	if len(cdlColumns) > 0 {
+		setStructured(metadata, d.metadataKey, cdlField, cdlColumns)
+	}
+
+	// Record logged columns that filled a clip property under another name
+	var logged map[string]interface{}
+	for _, canonical := range []string{ColumnName, ColumnStart, ColumnEnd, ColumnDuration, ColumnSourceFile} {
+		col := d.column(canonical)
+		if canonical == ColumnSourceFile {
+			col = sourceColumn
+		}
+		if col == canonical || row.value(col) == "" {
+			continue
+		}
+		if logged == nil {
+			logged = make(map[string]interface{})
+		}
+		logged[canonical] = col
+	}
+	if logged != nil {
+		setStructured(metadata, d.metadataKey, columnsField, logged)
+	}
+
+	// Normalize date columns to ISO 8601, keeping the original strings above
+	dates := make(map[string]interface{})
+	for key, value := range row.All() {
+		if !d.isDateColumn(key) {
+			continue
+		}
+		if iso, ok := parseDate(value, d.dateLayouts, d.dayFirst); ok {
+			dates[key] = iso
+		}
+	}
+	if len(dates) > 0 {
+		setStructured(metadata, d.metadataKey, datesField, dates)
+	}
+
+	if pulldownInfo != nil {
+		setStructured(metadata, d.metadataKey, pulldownField, pulldownInfo)
+	}
+
+	if d.stereo {
+		if stereo := stereoMetadata(row); stereo != nil {
+			setStructured(metadata, d.metadataKey, stereoField, stereo)
+		}
+	}
+
+	if d.geometry {
+		if geometry := imageGeometry(row.Get); geometry != nil {
+			setStructured(metadata, d.metadataKey, geometryField, geometry)
+		}
+	}
+	if d.color {
+		if pipeline := colorPipeline(row.Get); pipeline != nil {
+			setStructured(metadata, d.metadataKey, colorField, pipeline)
+		}
+	}
+
+	// Off-speed rows play through a time warp
+	var effects []gotio.Effect
+	if d.varispeed {
+		warp, err := d.varispeedWarp(row, index)
+		if err != nil {
+			warnings = append(warnings, err)
+		} else if warp != nil {
+			effects = append(effects, warp)
+		}
+	}
+
+	if cdl != nil && d.cdlEffect {
+		effects = append(effects, cdlEffect(cdl))
 	}
 
 	// Create and return clip
@@ -446,11 +840,36 @@ func (d *Decoder) rowToClip(row map[string]string, index int) (*gotio.Clip, erro
 		mediaRef,
 		sourceRange,
 		metadata,
-		nil, // effects
+		effects,
 		nil, // markers
 		"",  // activeMediaReferenceKey (use default)
 		nil, // color
 	)
 
-	return clip, nil
+	return clip, warnings, nil
+}
+
+// parseCell converts a cell to its declared type, using the decoder's
+// frame rate and date settings
+func (d *Decoder) parseCell(spec ColumnSpec, value string) (interface{}, error) {
+	if spec.Type == TypeDate {
+		if _, ok := parseDate(value, d.dateLayouts, d.dayFirst); !ok {
+			return nil, fmt.Errorf("unrecognized date format")
+		}
+		return value, nil
+	}
+	return parseCell(spec, value, d.fps)
+}
+
+// isDateColumn reports whether a column is parsed as a date
+func (d *Decoder) isDateColumn(column string) bool {
+	if spec, ok := d.schema.Lookup(column); ok && spec.Type == TypeDate {
+		return true
+	}
+	for _, col := range d.dateColumns {
+		if col == column {
+			return true
+		}
+	}
+	return false
 }
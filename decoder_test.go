@@ -4,7 +4,10 @@
 package ale
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"os"
 	"strings"
 	"testing"
 
@@ -301,3 +304,193 @@ func TestIsDropFrame(t *testing.T) {
 		})
 	}
 }
+
+func TestDecoder_WithNameSources(t *testing.T) {
+	aleContent := `Heading
+FIELD_DELIM	TABS
+
+Column
+Name	Clipname	Scene	Take	Camera	Duration
+
+Data
+Named	A001C001	19A	1	A	50
+	A001C002	19A	2	A	50
+		19A	3	B	50
+		19B		B	50
+`
+
+	decoder := NewDecoder(strings.NewReader(aleContent), WithNameSources(ColumnName, "Clipname", "{Scene}-{Take}{Camera}"))
+	timeline, err := decoder.Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode ALE: %v", err)
+	}
+
+	want := []string{"Named", "A001C002", "19A-3B", "Clip 4"}
+	clips := timeline.FindClips(nil, false)
+	if len(clips) != len(want) {
+		t.Fatalf("Expected %d clips, got %d", len(want), len(clips))
+	}
+	for i, clip := range clips {
+		if clip.Name() != want[i] {
+			t.Errorf("Clip %d name = '%s', want '%s'", i, clip.Name(), want[i])
+		}
+	}
+}
+
+func TestExpandNameTemplate(t *testing.T) {
+	row := map[string]string{"Scene": "19A", "Take": "2", "Camera": "A"}
+
+	tests := []struct {
+		template string
+		want     string
+		wantOk   bool
+	}{
+		{"{Scene}-{Take}{Camera}", "19A-2A", true},
+		{"Sc{ Scene }_Tk{Take}", "Sc19A_Tk2", true},
+		{"{Scene}-{Roll}", "19A-", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			got, ok := expandNameTemplate(tt.template, func(col string) string { return row[col] })
+			if ok != tt.wantOk || (ok && got != tt.want) {
+				t.Errorf("expandNameTemplate(%q) = (%q, %v), want (%q, %v)", tt.template, got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+// wideALE repeats the rows of sample.ale, which has about 150 columns
+func wideALE(b *testing.B, rows int) []byte {
+	b.Helper()
+	data, err := os.ReadFile("testdata/sample.ale")
+	if err != nil {
+		b.Fatalf("Failed to read test file: %v", err)
+	}
+
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	i := strings.Index(text, HeaderData+"\n")
+	head, body := text[:i+len(HeaderData)+1], strings.TrimRight(text[i+len(HeaderData)+1:], "\n")
+	sampleRows := strings.Split(body, "\n")
+
+	var buf strings.Builder
+	buf.WriteString(head)
+	for i := range rows {
+		buf.WriteString(sampleRows[i%len(sampleRows)])
+		buf.WriteByte('\n')
+	}
+	return []byte(buf.String())
+}
+
+func BenchmarkParseALE(b *testing.B) {
+	data := wideALE(b, 10_000)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+
+	for b.Loop() {
+		if _, err := NewDecoder(bytes.NewReader(data)).parseALE(context.Background()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestALEFile_Rows(t *testing.T) {
+	input := "Heading\nFIELD_DELIM\tTABS\n\nColumn\nName\tReel\tStart\n\nData\nA001\tR1 \t01:00:00:00\textra\nA002\n"
+	aleFile, err := NewDecoder(strings.NewReader(input)).parseALE(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to parse ALE: %v", err)
+	}
+
+	// Short rows are padded and long rows trimmed to the columns
+	for i, row := range aleFile.Rows {
+		if len(row) != len(aleFile.Columns) {
+			t.Errorf("Row %d has %d values, want %d", i, len(row), len(aleFile.Columns))
+		}
+	}
+
+	row := aleFile.Row(0)
+	if got := row.Get("Reel"); got != "R1" {
+		t.Errorf("Get(Reel) = %q, want R1", got)
+	}
+	if got := row.Get("Missing"); got != "" {
+		t.Errorf("Get(Missing) = %q, want empty", got)
+	}
+
+	want := []map[string]string{
+		{"Name": "A001", "Reel": "R1", "Start": "01:00:00:00"},
+		{"Name": "A002", "Reel": "", "Start": ""},
+	}
+	maps := aleFile.RowMaps()
+	if len(maps) != len(want) {
+		t.Fatalf("RowMaps returned %d rows, want %d", len(maps), len(want))
+	}
+	for i := range want {
+		for col, value := range want[i] {
+			if got, ok := maps[i][col]; !ok || got != value {
+				t.Errorf("RowMaps()[%d][%q] = %q, want %q", i, col, got, value)
+			}
+		}
+	}
+}
+
+func TestALEFile_AssignedColumns(t *testing.T) {
+	aleFile := NewALEFile()
+	aleFile.SetColumns([]string{"Name", "Reel"})
+	aleFile.Rows = append(aleFile.Rows, []string{"A001", "R1"})
+
+	// Replacing the slice, at the same or a different length
+	aleFile.Columns = []string{"Name", "Tape"}
+	if got := aleFile.Row(0).Get("Tape"); got != "R1" {
+		t.Errorf("Get(Tape) after assigning Columns = %q, want R1", got)
+	}
+	if _, ok := aleFile.ColumnIndex("Reel"); ok {
+		t.Error("ColumnIndex(Reel) found a replaced column")
+	}
+	aleFile.Columns = append(aleFile.Columns, "Scene")
+	if i, ok := aleFile.ColumnIndex("Scene"); !ok || i != 2 {
+		t.Errorf("ColumnIndex(Scene) = %d, %v, want 2", i, ok)
+	}
+
+	// Renaming a column in place
+	aleFile.SetColumns([]string{"Name", "Reel"})
+	aleFile.Columns[1] = "Tape"
+	if _, ok := aleFile.ColumnIndex("Reel"); ok {
+		t.Error("ColumnIndex(Reel) found a renamed column")
+	}
+	if i, ok := aleFile.ColumnIndex("Tape"); !ok || i != 1 {
+		t.Errorf("ColumnIndex(Tape) after renaming = %d, %v, want 1", i, ok)
+	}
+	if got := aleFile.Row(0).Get("Tape"); got != "R1" {
+		t.Errorf("Get(Tape) after renaming = %q, want R1", got)
+	}
+}
+
+func TestReadALE_WriteTo(t *testing.T) {
+	data, err := os.ReadFile("testdata/sample2.ale")
+	if err != nil {
+		t.Fatalf("Failed to read test file: %v", err)
+	}
+	aleFile, err := ReadALE(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("ReadALE() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	n, err := aleFile.WriteTo(&buf)
+	if err != nil {
+		t.Fatalf("WriteTo() error = %v", err)
+	}
+	if n != int64(buf.Len()) {
+		t.Errorf("WriteTo() = %d, wrote %d bytes", n, buf.Len())
+	}
+
+	written, err := ReadALE(&buf)
+	if err != nil {
+		t.Fatalf("ReadALE() of written file error = %v", err)
+	}
+	if fmt.Sprint(written.Headers) != fmt.Sprint(aleFile.Headers) ||
+		fmt.Sprint(written.Columns) != fmt.Sprint(aleFile.Columns) ||
+		fmt.Sprint(written.Rows) != fmt.Sprint(aleFile.Rows) {
+		t.Error("Written file reads back differently")
+	}
+}
@@ -4,28 +4,43 @@
 package ale
 
 import (
+	"context"
 	"fmt"
 	"io"
-	"strings"
 
 	"github.com/Avalanche-io/gotio"
+	"github.com/Avalanche-io/gotio/opentime"
 )
 
 // Encoder writes OTIO timelines as ALE files
 type Encoder struct {
 	w         io.Writer
 	fps       float64
+	fpsSet    bool
 	dropFrame bool
 	columns   []string
+	dateStyle string
+	schema    *Schema
+	aliases   columnAliases
+	canonical bool
+
+	metadataKey string
+	cdlKey      string
+	layout      MetadataLayout
+	pulldown    bool
+	cdlDigits   int
+	cdlFormat   CDLFormat
 }
 
 // EncoderOption configures an Encoder
 type EncoderOption func(*Encoder)
 
-// WithEncoderFPS sets the frame rate for the encoder
+// WithEncoderFPS sets the frame rate for the encoder. Without it, the
+// FPS heading decoded with the timeline is used, then DefaultFPS.
 func WithEncoderFPS(fps float64) EncoderOption {
 	return func(e *Encoder) {
 		e.fps = fps
+		e.fpsSet = true
 	}
 }
 
@@ -43,6 +58,85 @@ func WithColumns(columns []string) EncoderOption {
 	}
 }
 
+// WithDateStyle sets the Go time layout used to write date columns.
+// By default date columns are written with their original strings.
+func WithDateStyle(layout string) EncoderOption {
+	return func(e *Encoder) {
+		e.dateStyle = layout
+	}
+}
+
+// WithEncoderSchema sets the column schema used to format typed values
+func WithEncoderSchema(schema *Schema) EncoderOption {
+	return func(e *Encoder) {
+		e.schema = schema
+	}
+}
+
+// WithEncoderColumnAliases adds aliases mapping column names to canonical
+// column names. Alias columns that are not present in clip metadata are
+// filled like their canonical column (e.g. "Reel" like "Tape").
+func WithEncoderColumnAliases(aliases map[string]string) EncoderOption {
+	return func(e *Encoder) {
+		e.aliases = newColumnAliases(aliases)
+	}
+}
+
+// WithCanonicalColumns sets whether alias columns are renamed to their
+// canonical Avid names on output, unless the canonical column is also present
+func WithCanonicalColumns(canonical bool) EncoderOption {
+	return func(e *Encoder) {
+		e.canonical = canonical
+	}
+}
+
+// WithEncoderMetadataKey sets the clip metadata key columns are read from
+func WithEncoderMetadataKey(key string) EncoderOption {
+	return func(e *Encoder) {
+		e.metadataKey = key
+	}
+}
+
+// WithEncoderCDLMetadataKey sets the clip metadata key CDL data is read from
+func WithEncoderCDLMetadataKey(key string) EncoderOption {
+	return func(e *Encoder) {
+		e.cdlKey = key
+	}
+}
+
+// WithEncoderMetadataLayout sets how columns are arranged under the metadata key
+func WithEncoderMetadataLayout(layout MetadataLayout) EncoderOption {
+	return func(e *Encoder) {
+		e.layout = layout
+	}
+}
+
+// WithEncoderPulldown writes Start, End and Duration as the 29.97 video
+// timecode showing each clip's 23.976 film frames through 2:3 pulldown,
+// with Pullin and Pullout phase columns and a 29.97 FPS heading
+func WithEncoderPulldown(enabled bool) EncoderOption {
+	return func(e *Encoder) {
+		e.pulldown = enabled
+	}
+}
+
+// WithCDLPrecision writes ASC_SOP and ASC_SAT values with a fixed number
+// of decimals (default: DefaultCDLPrecision)
+func WithCDLPrecision(digits int) EncoderOption {
+	return func(e *Encoder) {
+		e.cdlDigits = digits
+	}
+}
+
+// WithCDLFormat selects the columns grades are written to (default:
+// CDLFormatASC). CDL columns carried in clip metadata are still written,
+// filled from the same grade.
+func WithCDLFormat(format CDLFormat) EncoderOption {
+	return func(e *Encoder) {
+		e.cdlFormat = format
+	}
+}
+
 // NewEncoder creates a new ALE encoder
 func NewEncoder(w io.Writer, opts ...EncoderOption) *Encoder {
 	e := &Encoder{
@@ -50,6 +144,12 @@ func NewEncoder(w io.Writer, opts ...EncoderOption) *Encoder {
 		fps:       DefaultFPS,
 		dropFrame: false,
 		columns:   nil, // Will be determined automatically based on timeline content
+		aliases:   newColumnAliases(nil),
+
+		metadataKey: DefaultMetadataKey,
+		cdlKey:      DefaultCDLMetadataKey,
+		layout:      LayoutFlat,
+		cdlDigits:   DefaultCDLPrecision,
 	}
 	for _, opt := range opts {
 		opt(e)
@@ -59,40 +159,74 @@ func NewEncoder(w io.Writer, opts ...EncoderOption) *Encoder {
 
 // Encode writes an OTIO Timeline as an ALE file
 func (e *Encoder) Encode(timeline *gotio.Timeline) error {
+	return e.EncodeContext(context.Background(), timeline)
+}
+
+// EncodeContext is like Encode but stops between rows once ctx is done,
+// returning an error wrapping ctx.Err(). The file is written in a single
+// call after all rows are converted, so a cancelled encode writes nothing.
+func (e *Encoder) EncodeContext(ctx context.Context, timeline *gotio.Timeline) error {
 	if timeline == nil {
 		return fmt.Errorf("timeline cannot be nil")
 	}
 
-	aleFile, err := e.timelineToALE(timeline)
+	aleFile, err := e.timelineToALE(ctx, timeline)
 	if err != nil {
 		return fmt.Errorf("failed to convert timeline to ALE: %w", err)
 	}
 
-	return e.writeALE(aleFile)
+	return e.writeALE(ctx, aleFile)
 }
 
 // timelineToALE converts an OTIO Timeline to an ALEFile structure
-func (e *Encoder) timelineToALE(timeline *gotio.Timeline) (*ALEFile, error) {
-	aleFile := NewALEFile()
+func (e *Encoder) timelineToALE(ctx context.Context, timeline *gotio.Timeline) (*ALEFile, error) {
+	hasTracks := len(timeline.VideoTracks()) > 0 || len(timeline.AudioTracks()) > 0
+	return e.clipsToALE(ctx, timeline.FindClips(nil, false), hasTracks, e.headingValues(timeline.Metadata()))
+}
 
-	// Get clips first to infer video format
-	clips := timeline.FindClips(nil, false)
+// clipsToALE converts clips to an ALEFile structure, with a Tracks column
+// when hasTracks is set. Decoded heading entries are written back, except
+// FIELD_DELIM and FPS which follow the encoder settings.
+func (e *Encoder) clipsToALE(ctx context.Context, clips []*gotio.Clip, hasTracks bool, heading map[string]string) (*ALEFile, error) {
+	// Without WithEncoderFPS, encode at the decoded frame rate
+	if !e.fpsSet {
+		if fps, err := parseFPS(heading[HeaderFPS]); err == nil {
+			encoder := *e
+			encoder.fps, encoder.fpsSet = fps, true
+			return encoder.clipsToALE(ctx, clips, hasTracks, heading)
+		}
+	}
+
+	aleFile := NewALEFile()
 
 	// Set headers
+	for key, value := range heading {
+		aleFile.Headers[key] = value
+	}
 	aleFile.Headers[HeaderFieldDelim] = DefaultFieldDelim
-	aleFile.Headers[HeaderAudioFormat] = "48kHz"
 	aleFile.Headers[HeaderFPS] = fmt.Sprintf("%.2f", e.fps)
+	if e.pulldown {
+		aleFile.Headers[HeaderFPS] = fmt.Sprintf("%.2f", VideoFPS)
+	}
+	if _, ok := aleFile.Headers[HeaderAudioFormat]; !ok {
+		aleFile.Headers[HeaderAudioFormat] = "48kHz"
+	}
 
 	// Infer video format from clip metadata
-	videoFormat := e.inferVideoFormat(clips)
-	aleFile.Headers[HeaderVideoFormat] = videoFormat
+	if _, ok := aleFile.Headers[HeaderVideoFormat]; !ok {
+		aleFile.Headers[HeaderVideoFormat] = e.inferVideoFormat(clips)
+	}
 
 	// Determine columns from clips
-	columns := e.determineColumns(timeline)
-	aleFile.Columns = columns
+	columns := e.determineColumns(clips, hasTracks)
+	aleFile.SetColumns(columns)
 
 	// Convert clips to rows
-	for _, clip := range clips {
+	for i, clip := range clips {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("encode cancelled at row %d: %w", i, err)
+		}
+
 		row, err := e.clipToRow(clip, columns)
 		if err != nil {
 			return nil, fmt.Errorf("failed to convert clip '%s' to row: %w", clip.Name(), err)
@@ -100,36 +234,62 @@ func (e *Encoder) timelineToALE(timeline *gotio.Timeline) (*ALEFile, error) {
 		aleFile.Rows = append(aleFile.Rows, row)
 	}
 
+	if e.canonical {
+		e.renameCanonicalColumns(aleFile)
+	}
+
 	return aleFile, nil
 }
 
-// inferVideoFormat infers the Avid video format from clip metadata
+// renameCanonicalColumns renames alias columns to their canonical names
+// when the canonical column is not already present
+func (e *Encoder) renameCanonicalColumns(aleFile *ALEFile) {
+	present := make(map[string]bool)
+	for _, col := range aleFile.Columns {
+		present[col] = true
+	}
+
+	// Copy so a caller's WithColumns slice is left untouched
+	columns := append([]string(nil), aleFile.Columns...)
+	for i, col := range columns {
+		if !e.aliases.isAlias(col) {
+			continue
+		}
+		canonical := e.aliases.canonical(col)
+		if present[canonical] {
+			continue
+		}
+		present[canonical] = true
+		columns[i] = canonical
+	}
+
+	aleFile.SetColumns(columns)
+}
+
+// inferVideoFormat infers the Avid video format from the clips' image
+// geometry, using the raster dimension when it is known and the image
+// size otherwise
 func (e *Encoder) inferVideoFormat(clips []*gotio.Clip) string {
 	maxWidth := 0
 	maxHeight := 0
 
-	// Look for clips with "Image Size" metadata
 	for _, clip := range clips {
 		metadata := clip.Metadata()
 		if metadata == nil {
 			continue
 		}
 
-		// Check in ALE metadata
-		if aleData, ok := metadata["ALE"]; ok {
-			if aleMap, ok := aleData.(map[string]interface{}); ok {
-				if imageSize, ok := aleMap["Image Size"]; ok {
-					if imageSizeStr, ok := imageSize.(string); ok {
-						w, h, ok := parseImageSize(imageSizeStr)
-						if ok {
-							if h > maxHeight {
-								maxHeight = h
-								maxWidth = w
-							}
-						}
-					}
-				}
-			}
+		geometry := e.clipGeometry(metadata, columnMetadata(metadata, e.metadataKey, e.layout))
+		if geometry == nil {
+			continue
+		}
+		w, h := geometry.RasterWidth, geometry.RasterHeight
+		if w == 0 || h == 0 {
+			w, h = geometry.Width, geometry.Height
+		}
+		if h > maxHeight {
+			maxHeight = h
+			maxWidth = w
 		}
 	}
 
@@ -142,8 +302,8 @@ func (e *Encoder) inferVideoFormat(clips []*gotio.Clip) string {
 	return "1080"
 }
 
-// determineColumns determines which columns to include based on the timeline content
-func (e *Encoder) determineColumns(timeline *gotio.Timeline) []string {
+// determineColumns determines which columns to include based on the clips
+func (e *Encoder) determineColumns(clips []*gotio.Clip, hasTracks bool) []string {
 	if len(e.columns) > 0 {
 		return e.columns
 	}
@@ -151,19 +311,21 @@ func (e *Encoder) determineColumns(timeline *gotio.Timeline) []string {
 	// Start with essential columns
 	cols := []string{ColumnName, ColumnStart, ColumnEnd, ColumnDuration}
 
-	// Check if we have audio or video tracks
-	hasVideo := len(timeline.VideoTracks()) > 0
-	hasAudio := len(timeline.AudioTracks()) > 0
-
-	if hasVideo || hasAudio {
+	if hasTracks {
 		cols = append(cols, ColumnTracks)
 	}
 
-	// Track which extra columns we've seen
+	// Track which extra columns we've seen, and the logged names of
+	// clip property columns
 	extraColumns := make(map[string]bool)
+	logged := make(map[string]string)
+
+	if e.pulldown {
+		extraColumns[ColumnPullin] = true
+		extraColumns[ColumnPullout] = true
+	}
 
 	// Add source file if any clip has a media reference
-	clips := timeline.FindClips(nil, false)
 	for _, clip := range clips {
 		ref := clip.MediaReference()
 		if ref != nil {
@@ -177,25 +339,72 @@ func (e *Encoder) determineColumns(timeline *gotio.Timeline) []string {
 
 		// Scan clip metadata for ALE columns to preserve
 		metadata := clip.Metadata()
-		if aleData, ok := metadata["ALE"]; ok {
-			if aleMap, ok := aleData.(map[string]interface{}); ok {
-				for key := range aleMap {
-					extraColumns[key] = true
+		values := columnMetadata(metadata, e.metadataKey, e.layout)
+		for key := range values {
+			extraColumns[key] = true
+		}
+		for canonical, col := range loggedColumns(metadata, e.metadataKey) {
+			if _, ok := logged[canonical]; !ok {
+				logged[canonical] = col
+			}
+		}
+
+		// Check for CDL metadata to add the columns of the CDL format and
+		// those the grade was decoded from
+		if cdl := clipCDL(clip, e.cdlKey); cdl != nil {
+			for _, col := range stringList(structured(metadata, e.metadataKey, cdlField)) {
+				extraColumns[col] = true
+			}
+			sopColumns, satColumn := e.cdlFormat.columns()
+			if cdl.ASCSOP != nil || (cdl.ASCSat != nil && satColumn == "") {
+				for _, col := range sopColumns {
+					extraColumns[col] = true
+				}
+			}
+			if cdl.ASCSat != nil && satColumn != "" {
+				extraColumns[satColumn] = true
+			}
+			for _, col := range []string{ColumnColorCorrID, ColumnCDLDescription, ColumnCDLInputDescription, ColumnCDLViewingDescription} {
+				if _, ok := cdl.columnValue(col); ok {
+					extraColumns[col] = true
 				}
 			}
 		}
 
-		// Check for CDL metadata to add ASC_SOP and ASC_SAT columns
-		if cdlData, ok := metadata["cdl"]; ok {
-			if cdl, ok := cdlData.(*CDLData); ok {
-				if cdl.ASCSOP != nil {
-					extraColumns["ASC_SOP"] = true
+		// Write the columns set by the clip's image geometry
+		if geometry := e.clipGeometry(metadata, values); geometry != nil {
+			for _, col := range geometryColumns {
+				if _, ok := geometry.columnValue(col); ok {
+					extraColumns[col] = true
 				}
-				if cdl.ASCSat != nil {
-					extraColumns["ASC_SAT"] = true
+			}
+		}
+
+		// Write the columns set by the clip's color pipeline
+		if pipeline := e.clipColorPipeline(metadata, values); pipeline != nil {
+			for _, col := range colorColumns {
+				if _, ok := pipeline.columnValue(col); ok {
+					extraColumns[col] = true
 				}
 			}
 		}
+
+		// Carry multicam groups from WithMulticamGroups
+		if _, ok := multicamGroupName(metadata, e.metadataKey); ok {
+			extraColumns[ColumnMulticamGroup] = true
+		}
+
+		// Write time warps as capture rate; Speed is only filled when logged
+		if _, ok := clipTimeScalar(clip); ok {
+			extraColumns[ColumnCFPS] = true
+		}
+
+		// Write each eye of a stereo pair from WithStereoPairs
+		for _, c := range stereoColumns {
+			if _, ok := e.stereoValue(metadata, c.column); ok {
+				extraColumns[c.column] = true
+			}
+		}
 	}
 
 	// Add extra columns in sorted order for consistency
@@ -214,12 +423,26 @@ func (e *Encoder) determineColumns(timeline *gotio.Timeline) []string {
 
 	cols = append(cols, extraCols...)
 
+	// Write clip properties back to the columns they were logged in
+	if !e.canonical {
+		present := make(map[string]bool, len(cols))
+		for _, col := range cols {
+			present[col] = true
+		}
+		for i, col := range cols {
+			if name, ok := logged[col]; ok && !present[name] {
+				present[name] = true
+				cols[i] = name
+			}
+		}
+	}
+
 	return cols
 }
 
-// clipToRow converts an OTIO Clip to an ALE row
-func (e *Encoder) clipToRow(clip *gotio.Clip, columns []string) (map[string]string, error) {
-	row := make(map[string]string)
+// clipToRow converts an OTIO Clip to an ALE row with a value per column
+func (e *Encoder) clipToRow(clip *gotio.Clip, columns []string) ([]string, error) {
+	row := make([]string, len(columns))
 
 	// Get source range
 	sourceRange := clip.SourceRange()
@@ -231,91 +454,171 @@ func (e *Encoder) clipToRow(clip *gotio.Clip, columns []string) (map[string]stri
 		}
 	}
 
-	// Get metadata once
+	// Get metadata and the logged column values once
 	metadata := clip.Metadata()
+	values := columnMetadata(metadata, e.metadataKey, e.layout)
+	logged := loggedColumns(metadata, e.metadataKey)
+
+	// With pulldown, timecodes are written as the 29.97 video frames that
+	// show the clip's film frames
+	fps, dropFrame := e.fps, e.dropFrame
+	var pullin, pullout string
+	if e.pulldown && sourceRange != nil {
+		start, end, in, out := e.pulldownVideoRange(sourceRange, metadata)
+		video := opentime.NewTimeRange(
+			opentime.NewRationalTime(float64(start), VideoFPS),
+			opentime.NewRationalTime(float64(end-start), VideoFPS),
+		)
+		sourceRange = &video
+		fps, dropFrame = VideoFPS, false
+		pullin, pullout = in, out
+	}
 
 	// Fill in column values
-	for _, col := range columns {
-		switch col {
+	for i, col := range columns {
+		switch e.columnRole(col, values, logged) {
 		case ColumnName:
-			row[col] = clip.Name()
+			row[i] = clip.Name()
 
 		case ColumnStart:
 			if sourceRange != nil {
 				startTime := sourceRange.StartTime()
-				tc, err := formatTimecode(startTime, e.fps, e.dropFrame)
+				tc, err := formatTimecode(startTime, fps, dropFrame)
 				if err != nil {
 					return nil, fmt.Errorf("failed to format start timecode: %w", err)
 				}
-				row[col] = tc
+				row[i] = tc
 			}
 
 		case ColumnEnd:
 			if sourceRange != nil {
 				endTime := sourceRange.EndTimeExclusive()
-				tc, err := formatTimecode(endTime, e.fps, e.dropFrame)
+				tc, err := formatTimecode(endTime, fps, dropFrame)
 				if err != nil {
 					return nil, fmt.Errorf("failed to format end timecode: %w", err)
 				}
-				row[col] = tc
+				row[i] = tc
 			}
 
 		case ColumnDuration:
 			if sourceRange != nil {
 				duration := sourceRange.Duration()
-				row[col] = formatFrameNumber(duration, e.fps)
+				row[i] = formatFrameNumber(duration, fps)
 			}
 
 		case ColumnTracks:
 			// Determine if this is video, audio, or both
 			// For now, assume video
-			row[col] = "V"
+			row[i] = "V"
 
 		case ColumnSourceFile, ColumnTape:
+			// A logged Tape that did not fill the media reference is kept
+			if value, ok := values[col]; ok {
+				row[i] = formatCell(ColumnSpec{}, value)
+				continue
+			}
 			if ref := clip.MediaReference(); ref != nil {
 				if extRef, ok := ref.(*gotio.ExternalReference); ok {
-					row[col] = extRef.TargetURL()
+					row[i] = extRef.TargetURL()
+				}
+			}
+
+		case ColumnASCSOP, ColumnASCSat, ColumnCDL, ColumnASCSOPR, ColumnASCSOPG, ColumnASCSOPB,
+			ColumnSlope, ColumnOffset, ColumnPower, ColumnSaturation:
+			// Fill every CDL column from the grade, leaving unrelated
+			// Slope, Offset, Power or Saturation values as they are
+			value, logged := values[col]
+			if cdl := clipCDL(clip, e.cdlKey); cdl != nil && (!logged || readsAsGrade(col, formatCell(ColumnSpec{}, value))) {
+				if graded, ok := cdl.gradeValue(col, e.cdlDigits); ok {
+					row[i] = graded
+					continue
 				}
 			}
+			if logged {
+				row[i] = formatCell(ColumnSpec{}, value)
+			}
 
-		case "ASC_SOP":
-			// Check for CDL metadata
-			if metadata != nil {
-				if cdlData, ok := metadata["cdl"]; ok {
-					if cdl, ok := cdlData.(*CDLData); ok {
-						if cdl.ASCSOP != nil {
-							row[col] = formatASCSOP(cdl.ASCSOP)
-						}
-					}
+		case ColumnColorCorrID, ColumnCDLDescription, ColumnCDLInputDescription, ColumnCDLViewingDescription:
+			if cdl := clipCDL(clip, e.cdlKey); cdl != nil {
+				if value, ok := cdl.columnValue(col); ok {
+					row[i] = value
+					continue
 				}
 			}
+			if value, ok := values[col]; ok {
+				row[i] = formatCell(ColumnSpec{}, value)
+			}
 
-		case "ASC_SAT":
-			// Check for CDL metadata
-			if metadata != nil {
-				if cdlData, ok := metadata["cdl"]; ok {
-					if cdl, ok := cdlData.(*CDLData); ok {
-						if cdl.ASCSat != nil {
-							row[col] = fmt.Sprintf("%.1f", *cdl.ASCSat)
-						}
-					}
+		case ColumnCFPS, ColumnSpeed:
+			value, logged := values[col]
+			if scalar, ok := clipTimeScalar(clip); ok {
+				rate := e.fps / scalar
+				if col == ColumnSpeed {
+					rate = scalar * 100
+				}
+				if !logged || !sameRate(formatCell(ColumnSpec{}, value), rate) {
+					row[i] = formatRate(rate)
+					continue
 				}
 			}
+			if logged {
+				row[i] = formatCell(ColumnSpec{}, value)
+			}
 
-		default:
-			// Check clip metadata["ALE"] for custom columns
-			if metadata != nil {
-				if aleData, ok := metadata["ALE"]; ok {
-					if aleMap, ok := aleData.(map[string]interface{}); ok {
-						if value, ok := aleMap[col]; ok {
-							if strValue, ok := value.(string); ok {
-								row[col] = strValue
-							} else {
-								row[col] = fmt.Sprintf("%v", value)
-							}
-						}
-					}
+		case ColumnPullin, ColumnPullout:
+			if pullin != "" {
+				row[i] = pullin
+				if col == ColumnPullout {
+					row[i] = pullout
 				}
+			} else if value, ok := values[col]; ok {
+				row[i] = formatCell(ColumnSpec{}, value)
+			}
+
+		case ColumnS3DClipName, ColumnS3DGroupName, ColumnS3DChannel,
+			ColumnS3DEyeOrder, ColumnS3DLeadingEye, ColumnS3DAlignment,
+			ColumnS3DInversion, ColumnS3DInversionR:
+			if value, ok := e.stereoValue(metadata, col); ok {
+				row[i] = value
+			} else if value, ok := values[col]; ok {
+				row[i] = formatCell(ColumnSpec{}, value)
+			}
+
+		case ColumnImageSize, ColumnRasterDimension, ColumnImageFraming,
+			ColumnImageAspectRatio, ColumnPixelAspectRatio, ColumnReformat, ColumnAFD:
+			if value, ok := e.geometryValue(metadata, values, col); ok {
+				row[i] = value
+			} else if value, ok := values[col]; ok {
+				spec, _ := e.schema.Lookup(col)
+				row[i] = formatCell(spec, value)
+			}
+
+		case ColumnColorSpace, ColumnLUT, ColumnColorTransformation:
+			if value, ok := e.colorValue(metadata, values, col); ok {
+				row[i] = value
+			} else if value, ok := values[col]; ok {
+				spec, _ := e.schema.Lookup(col)
+				row[i] = formatCell(spec, value)
+			}
+
+		case ColumnMulticamGroup:
+			if group, ok := multicamGroupName(metadata, e.metadataKey); ok {
+				row[i] = group
+			} else if value, ok := values[col]; ok {
+				row[i] = formatCell(ColumnSpec{}, value)
+			}
+
+		default:
+			// Restyle date columns from their normalized ISO values
+			if value, ok := e.styledDate(metadata, col); ok {
+				row[i] = value
+				continue
+			}
+
+			// Check clip column metadata for custom columns
+			if value, ok := values[col]; ok {
+				spec, _ := e.schema.Lookup(col)
+				row[i] = formatCell(spec, value)
 			}
 		}
 	}
@@ -323,38 +626,92 @@ func (e *Encoder) clipToRow(clip *gotio.Clip, columns []string) (map[string]stri
 	return row, nil
 }
 
-// writeALE writes the ALEFile structure to the output writer
-func (e *Encoder) writeALE(aleFile *ALEFile) error {
-	var lines []string
-
-	// Write Heading section
-	lines = append(lines, HeaderHeading)
-	for key, value := range aleFile.Headers {
-		lines = append(lines, fmt.Sprintf("%s\t%s", key, value))
-	}
-	lines = append(lines, "")
-
-	// Write Column section
-	lines = append(lines, HeaderColumn)
-	lines = append(lines, joinTabs(aleFile.Columns))
-	lines = append(lines, "")
-
-	// Write Data section
-	lines = append(lines, HeaderData)
-	for _, row := range aleFile.Rows {
-		values := make([]string, len(aleFile.Columns))
-		for i, col := range aleFile.Columns {
-			values[i] = row[col]
+// columnRole returns the canonical column an output column is filled as.
+// Columns carried in clip metadata keep their own values.
+func (e *Encoder) columnRole(col string, values map[string]interface{}, logged map[string]string) string {
+	if _, ok := values[col]; ok {
+		return col
+	}
+	for canonical, name := range logged {
+		if name == col {
+			return canonical
+		}
+	}
+	if e.aliases.isAlias(col) {
+		return e.aliases.canonical(col)
+	}
+	return col
+}
+
+// loggedColumns returns the logged columns that filled a clip's
+// properties, keyed by canonical column
+func loggedColumns(metadata gotio.AnyDictionary, metadataKey string) map[string]string {
+	logged := make(map[string]string)
+	for canonical, col := range asMap(structured(metadata, metadataKey, columnsField)) {
+		if s, ok := col.(string); ok {
+			logged[canonical] = s
 		}
-		lines = append(lines, joinTabs(values))
 	}
+	return logged
+}
+
+// columnMetadata returns the column values stored under metadataKey as a
+// flat map, with typed values that are unchanged since decoding in their
+// logged spelling
+func columnMetadata(metadata gotio.AnyDictionary, metadataKey string, layout MetadataLayout) map[string]interface{} {
+	values := flattenColumns(metadata[metadataKey], layout)
+	restoreRaw(values, asMap(structured(metadata, metadataKey, rawField)))
+	return values
+}
+
+// clipCDL returns a clip's CDL data from its CDL metadata under cdlKey,
+// or from an ASC CDL Effect when the metadata has none
+func clipCDL(clip *gotio.Clip, cdlKey string) *CDLData {
+	if cdl := cdlValue(clip.Metadata()[cdlKey]); cdl != nil {
+		return cdl
+	}
+	if effect := clipCDLEffect(clip); effect != nil {
+		return cdlValue(effect.Metadata()[cdlEffectKey])
+	}
+	return nil
+}
+
+// setClipCDL replaces the grade clipCDL reads: the one in the CDL
+// metadata, or an ASC CDL Effect's when the metadata has none. Clips with
+// neither get the grade in their metadata.
+func setClipCDL(clip *gotio.Clip, cdlKey string, cdl *CDLData) {
+	metadata := clip.Metadata()
+	if cdlValue(metadata[cdlKey]) == nil {
+		if effect := clipCDLEffect(clip); effect != nil {
+			effect.Metadata()[cdlEffectKey] = cdl
+			return
+		}
+	}
+	if metadata != nil {
+		metadata[cdlKey] = cdl
+	}
+}
 
-	// Write all lines to the writer
-	output := strings.Join(lines, "\n")
-	if !strings.HasSuffix(output, "\n") {
-		output += "\n"
+// styledDate formats a date column in the configured date style
+func (e *Encoder) styledDate(metadata gotio.AnyDictionary, col string) (string, bool) {
+	if e.dateStyle == "" || metadata == nil {
+		return "", false
+	}
+	dates := asMap(structured(metadata, e.metadataKey, datesField))
+	iso, ok := dates[col].(string)
+	if !ok {
+		return "", false
+	}
+	return formatDate(iso, e.dateStyle)
+}
+
+// writeALE writes the ALEFile structure to the output writer
+func (e *Encoder) writeALE(ctx context.Context, aleFile *ALEFile) error {
+	// Last chance to stop before anything reaches the writer
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("encode cancelled before writing: %w", err)
 	}
 
-	_, err := e.w.Write([]byte(output))
+	_, err := aleFile.WriteTo(e.w)
 	return err
 }
@@ -0,0 +1,322 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package ale
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/Avalanche-io/gotio"
+)
+
+// Image geometry columns
+const (
+	ColumnImageSize        = "Image Size"
+	ColumnRasterDimension  = "Raster Dimension"
+	ColumnImageFraming     = "Image Framing"
+	ColumnImageAspectRatio = "Image Aspect Ratio"
+	ColumnPixelAspectRatio = "Pixel Aspect Ratio"
+	ColumnReformat         = "Reformat"
+	ColumnAFD              = "AFD"
+)
+
+// geometryColumns lists the image geometry columns in the order the
+// encoder checks them
+var geometryColumns = []string{
+	ColumnImageSize,
+	ColumnRasterDimension,
+	ColumnImageFraming,
+	ColumnImageAspectRatio,
+	ColumnPixelAspectRatio,
+	ColumnReformat,
+	ColumnAFD,
+}
+
+// Framing and reformat modes
+const (
+	FramingStretch   = "stretch"
+	FramingCenter    = "center"
+	FramingLetterbox = "letterbox"
+	FramingCrop      = "crop"
+)
+
+// framingNames holds the Avid spelling of each framing mode
+var framingNames = map[string]string{
+	FramingStretch:   "Stretch",
+	FramingCenter:    "Center (Keep Size)",
+	FramingLetterbox: "Letterbox/Sidebar",
+	FramingCrop:      "Center Crop",
+}
+
+// aspectRatioNames holds the usual spelling of common display aspect
+// ratios; others are written as "N.NN:1"
+var aspectRatioNames = []struct {
+	ratio float64
+	name  string
+}{
+	{4.0 / 3.0, "4:3"},
+	{16.0 / 9.0, "16:9"},
+}
+
+// ImageGeometry is the image geometry of a clip, decoded from the Image
+// Size, Raster Dimension, Image Aspect Ratio, Pixel Aspect Ratio, Image
+// Framing, Reformat and AFD columns. Zero values are unset, except AFD,
+// where 0 is a valid code and nil is unset.
+type ImageGeometry struct {
+	Width         int     `json:"width,omitempty"`
+	Height        int     `json:"height,omitempty"`
+	RasterWidth   int     `json:"raster_width,omitempty"`
+	RasterHeight  int     `json:"raster_height,omitempty"`
+	PixelAspect   float64 `json:"pixel_aspect,omitempty"`
+	DisplayAspect float64 `json:"display_aspect,omitempty"`
+	Framing       string  `json:"framing,omitempty"`
+	Reformat      string  `json:"reformat,omitempty"`
+	AFD           *int    `json:"afd,omitempty"`
+}
+
+// PixelAspectRatio returns the pixel aspect ratio, or 1 if it is unset
+func (g *ImageGeometry) PixelAspectRatio() float64 {
+	if g.PixelAspect > 0 {
+		return g.PixelAspect
+	}
+	return 1
+}
+
+// DisplayAspectRatio returns the display aspect ratio, computing it from
+// the image size and pixel aspect ratio when it is unset. It returns 0 if
+// neither is known.
+func (g *ImageGeometry) DisplayAspectRatio() float64 {
+	if g.DisplayAspect > 0 {
+		return g.DisplayAspect
+	}
+	width, height := g.Width, g.Height
+	if width == 0 || height == 0 {
+		width, height = g.RasterWidth, g.RasterHeight
+	}
+	if width == 0 || height == 0 {
+		return 0
+	}
+	return float64(width) * g.PixelAspectRatio() / float64(height)
+}
+
+// isZero reports whether no geometry was set
+func (g *ImageGeometry) isZero() bool {
+	return *g == ImageGeometry{}
+}
+
+// imageGeometry reads the image geometry columns through column, or
+// returns nil if none of them has a value. Values that cannot be read are
+// left unset; their raw strings stay in the column metadata.
+func imageGeometry(column func(string) string) *ImageGeometry {
+	g := &ImageGeometry{}
+	if w, h, ok := parseImageSize(column(ColumnImageSize)); ok {
+		g.Width, g.Height = w, h
+	}
+	if w, h, ok := parseImageSize(column(ColumnRasterDimension)); ok {
+		g.RasterWidth, g.RasterHeight = w, h
+	}
+	if ratio, ok := parseAspectRatio(column(ColumnPixelAspectRatio)); ok {
+		g.PixelAspect = ratio
+	}
+	if ratio, ok := parseAspectRatio(column(ColumnImageAspectRatio)); ok {
+		g.DisplayAspect = ratio
+	}
+	g.Framing = parseFraming(column(ColumnImageFraming))
+	g.Reformat = parseFraming(column(ColumnReformat))
+	if afd, err := strconv.Atoi(strings.TrimSpace(column(ColumnAFD))); err == nil && afd >= 0 && afd <= 15 {
+		g.AFD = &afd
+	}
+
+	if g.isZero() {
+		return nil
+	}
+	return g
+}
+
+// imageGeometryFromMap reads an ImageGeometry stored as a map, as it is
+// after a JSON round trip
+func imageGeometryFromMap(m map[string]interface{}) *ImageGeometry {
+	number := func(key string) float64 {
+		switch v := m[key].(type) {
+		case float64:
+			return v
+		case int:
+			return float64(v)
+		case int64:
+			return float64(v)
+		}
+		return 0
+	}
+	g := &ImageGeometry{
+		Width:         int(number("width")),
+		Height:        int(number("height")),
+		RasterWidth:   int(number("raster_width")),
+		RasterHeight:  int(number("raster_height")),
+		PixelAspect:   number("pixel_aspect"),
+		DisplayAspect: number("display_aspect"),
+		Framing:       stringValue(m["framing"]),
+		Reformat:      stringValue(m["reformat"]),
+	}
+	if _, ok := m["afd"]; ok {
+		afd := int(number("afd"))
+		g.AFD = &afd
+	}
+	if g.isZero() {
+		return nil
+	}
+	return g
+}
+
+// parseAspectRatio parses a ratio written as "16:9", "2.39:1", "4/3" or
+// "1.000"
+func parseAspectRatio(value string) (float64, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	num, den, found := strings.Cut(strings.ReplaceAll(value, "/", ":"), ":")
+	n, err := strconv.ParseFloat(strings.TrimSpace(num), 64)
+	if err != nil || n <= 0 {
+		return 0, false
+	}
+	if !found {
+		return n, true
+	}
+	d, err := strconv.ParseFloat(strings.TrimSpace(den), 64)
+	if err != nil || d <= 0 {
+		return 0, false
+	}
+	return n / d, true
+}
+
+// formatAspectRatio formats a display aspect ratio as "16:9" or "2.39:1"
+func formatAspectRatio(ratio float64) string {
+	for _, r := range aspectRatioNames {
+		if math.Abs(ratio-r.ratio) < 0.005 {
+			return r.name
+		}
+	}
+	return strconv.FormatFloat(ratio, 'f', 2, 64) + ":1"
+}
+
+// parseFraming normalizes an Image Framing or Reformat value to one of the
+// framing modes. Unknown values are kept as written.
+func parseFraming(value string) string {
+	v := strings.ToLower(strings.TrimSpace(value))
+	switch {
+	case v == "":
+		return ""
+	case strings.Contains(v, "letterbox") || strings.Contains(v, "pillarbox") ||
+		strings.Contains(v, "sidebar") || strings.Contains(v, "fit"):
+		return FramingLetterbox
+	case strings.Contains(v, "crop") || strings.Contains(v, "fill"):
+		return FramingCrop
+	case strings.Contains(v, "stretch"):
+		return FramingStretch
+	case strings.Contains(v, "center") || strings.Contains(v, "keep size"):
+		return FramingCenter
+	}
+	return strings.TrimSpace(value)
+}
+
+// formatFraming returns the Avid spelling of a framing mode
+func formatFraming(mode string) string {
+	if name, ok := framingNames[mode]; ok {
+		return name
+	}
+	return mode
+}
+
+// columnValue formats a geometry column, or returns false if the geometry
+// does not set it
+func (g *ImageGeometry) columnValue(column string) (string, bool) {
+	switch column {
+	case ColumnImageSize:
+		if g.Width > 0 && g.Height > 0 {
+			return fmt.Sprintf("%d x %d", g.Width, g.Height), true
+		}
+	case ColumnRasterDimension:
+		if g.RasterWidth > 0 && g.RasterHeight > 0 {
+			return fmt.Sprintf("%dx%d", g.RasterWidth, g.RasterHeight), true
+		}
+	case ColumnPixelAspectRatio:
+		if g.PixelAspect > 0 {
+			return strconv.FormatFloat(g.PixelAspect, 'f', 3, 64), true
+		}
+	case ColumnImageAspectRatio:
+		if g.DisplayAspect > 0 {
+			return formatAspectRatio(g.DisplayAspect), true
+		}
+	case ColumnImageFraming:
+		if g.Framing != "" {
+			return formatFraming(g.Framing), true
+		}
+	case ColumnReformat:
+		if g.Reformat != "" {
+			return formatFraming(g.Reformat), true
+		}
+	case ColumnAFD:
+		if g.AFD != nil {
+			return strconv.Itoa(*g.AFD), true
+		}
+	}
+	return "", false
+}
+
+// clipGeometry returns a clip's image geometry from its geometry metadata,
+// as an ImageGeometry or a map, falling back to its geometry columns
+func (e *Encoder) clipGeometry(metadata gotio.AnyDictionary, columns map[string]interface{}) *ImageGeometry {
+	switch g := structured(metadata, e.metadataKey, geometryField).(type) {
+	case *ImageGeometry:
+		if g != nil {
+			return g
+		}
+	case ImageGeometry:
+		return &g
+	default:
+		if m := asMap(g); m != nil {
+			if geometry := imageGeometryFromMap(m); geometry != nil {
+				return geometry
+			}
+		}
+	}
+
+	return imageGeometry(func(column string) string {
+		if value, ok := columns[column]; ok {
+			return formatCell(ColumnSpec{}, value)
+		}
+		return ""
+	})
+}
+
+// geometryValue returns the value of a geometry column from a clip's
+// geometry. A logged value that reads back the same, such as "1920x1080p"
+// or "1.000", is written as logged.
+func (e *Encoder) geometryValue(metadata gotio.AnyDictionary, columns map[string]interface{}, column string) (string, bool) {
+	geometry := e.clipGeometry(metadata, columns)
+	if geometry == nil {
+		return "", false
+	}
+	value, ok := geometry.columnValue(column)
+	if !ok {
+		return "", false
+	}
+
+	if raw, ok := columns[column]; ok {
+		logged := formatCell(ColumnSpec{}, raw)
+		if g := imageGeometry(func(c string) string {
+			if c == column {
+				return logged
+			}
+			return ""
+		}); g != nil {
+			if v, ok := g.columnValue(column); ok && v == value {
+				return logged, true
+			}
+		}
+	}
+	return value, true
+}
@@ -0,0 +1,219 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package ale
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Avalanche-io/gotio"
+	"github.com/Avalanche-io/gotio/opentime"
+)
+
+func TestParseAspectRatio(t *testing.T) {
+	tests := []struct {
+		input string
+		want  float64
+		ok    bool
+	}{
+		{"16:9", 16.0 / 9.0, true},
+		{"2.39:1", 2.39, true},
+		{"4/3", 4.0 / 3.0, true},
+		{"1.000", 1, true},
+		{"2:0", 0, false},
+		{"wide", 0, false},
+		{"", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseAspectRatio(tt.input)
+		if ok != tt.ok || math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("parseAspectRatio(%q) = %v, %v; want %v, %v", tt.input, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestParseFraming(t *testing.T) {
+	tests := map[string]string{
+		"Stretch":            FramingStretch,
+		"Center (Keep Size)": FramingCenter,
+		"Letterbox/Sidebar":  FramingLetterbox,
+		"Pillarbox":          FramingLetterbox,
+		"Center Crop":        FramingCrop,
+		"Custom":             "Custom",
+		"":                   "",
+	}
+	for input, want := range tests {
+		if got := parseFraming(input); got != want {
+			t.Errorf("parseFraming(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestDecoder_ImageGeometry(t *testing.T) {
+	file, err := os.Open("testdata/sample.ale")
+	if err != nil {
+		t.Fatalf("Failed to open sample: %v", err)
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		t.Fatalf("Failed to read sample: %v", err)
+	}
+
+	// Geometry is only decoded on request
+	timeline, err := NewDecoder(bytes.NewReader(data)).Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode ALE: %v", err)
+	}
+	if g := structured(timeline.FindClips(nil, false)[0].Metadata(), DefaultMetadataKey, geometryField); g != nil {
+		t.Errorf("Geometry decoded without WithImageGeometry: %v", g)
+	}
+
+	timeline, err = NewDecoder(bytes.NewReader(data), WithImageGeometry(true)).Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode ALE: %v", err)
+	}
+
+	clip := timeline.FindClips(nil, false)[0]
+	geometry, ok := structured(clip.Metadata(), DefaultMetadataKey, geometryField).(*ImageGeometry)
+	if !ok {
+		t.Fatalf("Clip has no image geometry: %v", clip.Metadata())
+	}
+	want := ImageGeometry{
+		Width:         1920,
+		Height:        1080,
+		RasterWidth:   1920,
+		RasterHeight:  1080,
+		PixelAspect:   1,
+		DisplayAspect: 16.0 / 9.0,
+		Reformat:      FramingStretch,
+	}
+	if *geometry != want {
+		t.Errorf("Geometry = %+v, want %+v", *geometry, want)
+	}
+
+	// Logged spellings are written back unchanged
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).Encode(timeline); err != nil {
+		t.Fatalf("Failed to encode timeline: %v", err)
+	}
+	aleFile, err := NewDecoder(bytes.NewReader(buf.Bytes())).parseALE(t.Context())
+	if err != nil {
+		t.Fatalf("Failed to parse encoded ALE: %v", err)
+	}
+	row := aleFile.Row(0)
+	logged := map[string]string{
+		ColumnImageSize:        "1920 x 1080",
+		ColumnRasterDimension:  "1920x1080p",
+		ColumnImageAspectRatio: "16:9",
+		ColumnPixelAspectRatio: "1.000",
+		ColumnReformat:         "Stretch",
+	}
+	for col, value := range logged {
+		if got := row.Get(col); got != value {
+			t.Errorf("%s = %q, want %q", col, got, value)
+		}
+	}
+}
+
+func TestEncoder_ImageGeometry(t *testing.T) {
+	// Geometry set on a clip, in struct or JSON map form, fills the
+	// geometry columns and VIDEO_FORMAT. AFD 0 is a valid code.
+	afd := 0
+	geometry := &ImageGeometry{
+		Width:         2048,
+		Height:        858,
+		RasterWidth:   1280,
+		RasterHeight:  720,
+		PixelAspect:   2,
+		DisplayAspect: 2.39,
+		Framing:       FramingLetterbox,
+		AFD:           &afd,
+	}
+	data, err := json.Marshal(geometry)
+	if err != nil {
+		t.Fatalf("Failed to marshal geometry: %v", err)
+	}
+	var geometryMap map[string]interface{}
+	if err := json.Unmarshal(data, &geometryMap); err != nil {
+		t.Fatalf("Failed to unmarshal geometry: %v", err)
+	}
+
+	for name, value := range map[string]interface{}{"struct": geometry, "map": geometryMap} {
+		t.Run(name, func(t *testing.T) {
+			timeline := gotio.NewTimeline("Test Timeline", nil, nil)
+			track := gotio.NewTrack("Video", nil, gotio.TrackKindVideo, nil, nil)
+			sourceRange := opentime.NewTimeRange(
+				opentime.NewRationalTime(0, 24),
+				opentime.NewRationalTime(24, 24),
+			)
+			clip := gotio.NewClip("A001", nil, &sourceRange, gotio.AnyDictionary{DefaultMetadataKey: map[string]interface{}{geometryField: value}}, nil, nil, "", nil)
+			track.AppendChild(clip)
+			timeline.Tracks().AppendChild(track)
+
+			var buf bytes.Buffer
+			if err := NewEncoder(&buf).Encode(timeline); err != nil {
+				t.Fatalf("Failed to encode timeline: %v", err)
+			}
+			aleFile, err := NewDecoder(bytes.NewReader(buf.Bytes())).parseALE(t.Context())
+			if err != nil {
+				t.Fatalf("Failed to parse encoded ALE: %v", err)
+			}
+
+			if got := aleFile.Headers[HeaderVideoFormat]; got != "720" {
+				t.Errorf("VIDEO_FORMAT = %s, want 720 from the raster dimension", got)
+			}
+			want := map[string]string{
+				ColumnImageSize:        "2048 x 858",
+				ColumnRasterDimension:  "1280x720",
+				ColumnImageAspectRatio: "2.39:1",
+				ColumnPixelAspectRatio: "2.000",
+				ColumnImageFraming:     "Letterbox/Sidebar",
+				ColumnAFD:              "0",
+			}
+			row := aleFile.Row(0)
+			for col, value := range want {
+				if got := row.Get(col); got != value {
+					t.Errorf("%s = %q, want %q", col, got, value)
+				}
+			}
+			if strings.Contains(buf.String(), ColumnReformat) {
+				t.Error("Output has a Reformat column the geometry does not set")
+			}
+		})
+	}
+}
+
+func TestImageGeometry_DisplayAspectRatio(t *testing.T) {
+	// Anamorphic 2x squeeze of a 4:3 raster
+	g := &ImageGeometry{Width: 1440, Height: 1080, PixelAspect: 2}
+	if got := g.DisplayAspectRatio(); math.Abs(got-8.0/3.0) > 1e-9 {
+		t.Errorf("DisplayAspectRatio() = %v, want %v", got, 8.0/3.0)
+	}
+	if got := (&ImageGeometry{}).DisplayAspectRatio(); got != 0 {
+		t.Errorf("DisplayAspectRatio() of empty geometry = %v, want 0", got)
+	}
+}
+
+func TestImageGeometry_AFDZero(t *testing.T) {
+	columns := map[string]string{ColumnAFD: "0"}
+	g := imageGeometry(func(column string) string { return columns[column] })
+	if g == nil || g.AFD == nil || *g.AFD != 0 {
+		t.Fatalf("imageGeometry() = %+v, want AFD 0", g)
+	}
+	if value, ok := g.columnValue(ColumnAFD); !ok || value != "0" {
+		t.Errorf("columnValue(AFD) = %q, %v, want 0", value, ok)
+	}
+
+	columns[ColumnAFD] = "16"
+	if g := imageGeometry(func(column string) string { return columns[column] }); g != nil {
+		t.Errorf("imageGeometry() with AFD 16 = %+v, want nil", g)
+	}
+}
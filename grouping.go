@@ -0,0 +1,263 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package ale
+
+import (
+	"iter"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Avalanche-io/gotio"
+)
+
+// Row is a read-only view of an ALE data row
+type Row struct {
+	file    *ALEFile
+	values  []string
+	columns map[string]string
+}
+
+// Get returns the value of a column. Canonical column names such as "Tape"
+// also find the aliased column that provides them (e.g. "Reel").
+func (r Row) Get(column string) string {
+	if value, ok := r.lookup(column); ok {
+		return value
+	}
+	if col, ok := r.columns[column]; ok {
+		return r.value(col)
+	}
+	return ""
+}
+
+// All yields each column of the row with its value, in column order
+func (r Row) All() iter.Seq2[string, string] {
+	return func(yield func(string, string) bool) {
+		if r.file == nil {
+			return
+		}
+		for i, col := range r.file.Columns {
+			value := ""
+			if i < len(r.values) {
+				value = r.values[i]
+			}
+			if !yield(col, value) {
+				return
+			}
+		}
+	}
+}
+
+// value returns the value of a column present in the file, without alias
+// resolution
+func (r Row) value(column string) string {
+	value, _ := r.lookup(column)
+	return value
+}
+
+// lookup finds a column present in the file
+func (r Row) lookup(column string) (string, bool) {
+	if r.file == nil {
+		return "", false
+	}
+	i, ok := r.file.ColumnIndex(column)
+	if !ok || i >= len(r.values) {
+		return "", ok
+	}
+	return r.values[i], true
+}
+
+// TrackKeyFunc maps a row to the key and kind of the track it belongs to.
+// The key is used as the track name; an empty key falls back to the kind.
+type TrackKeyFunc func(row Row) (key, kind string)
+
+// TrackGrouping assigns rows to tracks
+type TrackGrouping struct {
+	// Key maps a row to its track
+	Key TrackKeyFunc
+	// Compare orders track keys of the same kind; nil uses natural order
+	Compare func(a, b string) int
+}
+
+// Built-in track groupings
+var (
+	// GroupByTracks groups clips by the Tracks column (V1, A1, VA1, ...)
+	GroupByTracks = TrackGrouping{Key: tracksKey, Compare: compareTrackKeys}
+	// GroupByCamera puts each camera on its own track
+	GroupByCamera = GroupByColumn(ColumnCamera)
+	// GroupByTape puts each tape or reel on its own track
+	GroupByTape = GroupByColumn(ColumnTape)
+	// GroupByScene puts each scene on its own track
+	GroupByScene = GroupByColumn(ColumnScene)
+	// GroupByShootDate puts each shoot day on its own track, in date order
+	GroupByShootDate = TrackGrouping{Key: shootDateKey}
+)
+
+// singleTrack puts every clip on one video track
+var singleTrack = TrackGrouping{
+	Key: func(Row) (string, string) {
+		return "Video", gotio.TrackKindVideo
+	},
+}
+
+// GroupByColumn puts clips with the same value in a column on one video
+// track named after the column and value (e.g. "Camera A")
+func GroupByColumn(column string) TrackGrouping {
+	return TrackGrouping{
+		Key: func(row Row) (string, string) {
+			return columnTrackKey(column, row.Get(column)), gotio.TrackKindVideo
+		},
+	}
+}
+
+// columnTrackKey names a track after a column and value
+func columnTrackKey(column, value string) string {
+	if value == "" {
+		return column
+	}
+	return column + " " + value
+}
+
+// tracksKey groups a row by its Tracks column value
+func tracksKey(row Row) (string, string) {
+	tracksValue := row.Get(ColumnTracks)
+	trackKey := tracksValue
+	if trackKey == "" {
+		trackKey = "V" // Default to video
+	}
+	return trackKey, parseTrackKind(tracksValue)
+}
+
+// shootDateKey groups a row by its shoot day, using ISO dates so that
+// natural key order is chronological
+func shootDateKey(row Row) (string, string) {
+	value := row.Get(ColumnShootDate)
+	if iso, ok := parseDate(value, DefaultDateLayouts, false); ok {
+		value = iso
+	}
+	return columnTrackKey(ColumnShootDate, value), gotio.TrackKindVideo
+}
+
+// sort orders track keys by kind (video, audio, other), then by key
+func (g TrackGrouping) sort(keys []string, kinds map[string]string) {
+	compare := g.Compare
+	if compare == nil {
+		compare = compareNatural
+	}
+
+	slices.SortStableFunc(keys, func(a, b string) int {
+		if c := trackKindRank(kinds[a]) - trackKindRank(kinds[b]); c != 0 {
+			return c
+		}
+		return compare(a, b)
+	})
+}
+
+// trackKindRank orders video tracks before audio tracks
+func trackKindRank(kind string) int {
+	switch kind {
+	case gotio.TrackKindVideo:
+		return 0
+	case gotio.TrackKindAudio:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// parseTrackKind determines the track kind from a Tracks column value
+func parseTrackKind(tracksValue string) string {
+	// Tracks column can be: V, A, VA, V1, A1, VA1, etc.
+	tracksValue = strings.TrimSpace(strings.ToUpper(tracksValue))
+
+	if strings.Contains(tracksValue, "V") && strings.Contains(tracksValue, "A") {
+		// Both video and audio - use video for primary
+		return gotio.TrackKindVideo
+	} else if strings.Contains(tracksValue, "A") {
+		return gotio.TrackKindAudio
+	}
+
+	// Default to video
+	return gotio.TrackKindVideo
+}
+
+// compareTrackKeys compares two track keys for sorting
+func compareTrackKeys(a, b string) int {
+	// V comes before A, VA comes before both
+	aHasV := strings.Contains(a, "V")
+	aHasA := strings.Contains(a, "A")
+	bHasV := strings.Contains(b, "V")
+	bHasA := strings.Contains(b, "A")
+
+	// Both have V and A
+	if aHasV && aHasA && bHasV && bHasA {
+		return compareNatural(a, b)
+	}
+
+	// Only a has both
+	if aHasV && aHasA {
+		return -1
+	}
+
+	// Only b has both
+	if bHasV && bHasA {
+		return 1
+	}
+
+	// V before A
+	if aHasV && bHasA {
+		return -1
+	}
+	if aHasA && bHasV {
+		return 1
+	}
+
+	// Both same type, compare by number
+	return compareNatural(a, b)
+}
+
+// compareNatural compares strings with embedded numbers numerically, so
+// "V2" sorts before "V10"
+func compareNatural(a, b string) int {
+	for a != "" && b != "" {
+		if isDigit(a[0]) && isDigit(b[0]) {
+			aNum, aRest := splitDigits(a)
+			bNum, bRest := splitDigits(b)
+			an, _ := strconv.ParseUint(aNum, 10, 64)
+			bn, _ := strconv.ParseUint(bNum, 10, 64)
+			if an != bn {
+				if an < bn {
+					return -1
+				}
+				return 1
+			}
+			a, b = aRest, bRest
+			continue
+		}
+
+		if a[0] != b[0] {
+			if a[0] < b[0] {
+				return -1
+			}
+			return 1
+		}
+		a, b = a[1:], b[1:]
+	}
+
+	return len(a) - len(b)
+}
+
+// splitDigits splits a string into its leading digits and the rest
+func splitDigits(s string) (string, string) {
+	i := 0
+	for i < len(s) && isDigit(s[i]) {
+		i++
+	}
+	return s[:i], s[i:]
+}
+
+// isDigit reports whether a byte is an ASCII digit
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,109 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package ale
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Avalanche-io/gotio"
+)
+
+const groupingALE = `Heading
+FIELD_DELIM	TABS
+
+Column
+Name	Tracks	Camera	Reel	Scene	Shoot Date	Duration
+
+Data
+Clip001	V2	B	R002	10	07/22/14	24
+Clip002	A1	A	R001	2	07/21/14	24
+Clip003	V10	A	R001	2	07/21/14	24
+Clip004	VA1	C	R003	10	07/22/14	24
+Clip005	V2	B	R002	2	07/23/14	24
+`
+
+func trackNames(timeline *gotio.Timeline) []string {
+	var names []string
+	for _, child := range timeline.Tracks().Children() {
+		names = append(names, child.(*gotio.Track).Name())
+	}
+	return names
+}
+
+func TestDecoder_WithTrackGrouping(t *testing.T) {
+	tests := []struct {
+		name     string
+		grouping TrackGrouping
+		want     []string
+	}{
+		{"tracks", GroupByTracks, []string{"VA1", "V2", "V10", "A1"}},
+		{"camera", GroupByCamera, []string{"Camera A", "Camera B", "Camera C"}},
+		{"tape via alias", GroupByTape, []string{"Tape R001", "Tape R002", "Tape R003"}},
+		{"scene", GroupByScene, []string{"Scene 2", "Scene 10"}},
+		{"shoot date", GroupByShootDate, []string{"Shoot Date 2014-07-21", "Shoot Date 2014-07-22", "Shoot Date 2014-07-23"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoder := NewDecoder(strings.NewReader(groupingALE), WithTrackGrouping(tt.grouping))
+			timeline, err := decoder.Decode()
+			if err != nil {
+				t.Fatalf("Failed to decode ALE: %v", err)
+			}
+
+			got := trackNames(timeline)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Track names = %v, want %v", got, tt.want)
+			}
+			if clips := timeline.FindClips(nil, false); len(clips) != 5 {
+				t.Errorf("Expected 5 clips, got %d", len(clips))
+			}
+		})
+	}
+}
+
+func TestDecoder_WithTrackKeyFunc(t *testing.T) {
+	decoder := NewDecoder(strings.NewReader(groupingALE), WithTrackKeyFunc(func(row Row) (string, string) {
+		if strings.HasPrefix(row.Get("Tracks"), "A") {
+			return "Sound", gotio.TrackKindAudio
+		}
+		return "Picture " + row.Get("Camera"), gotio.TrackKindVideo
+	}))
+	timeline, err := decoder.Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode ALE: %v", err)
+	}
+
+	want := []string{"Picture A", "Picture B", "Picture C", "Sound"}
+	if got := trackNames(timeline); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Track names = %v, want %v", got, want)
+	}
+	if len(timeline.AudioTracks()) != 1 {
+		t.Errorf("Expected 1 audio track, got %d", len(timeline.AudioTracks()))
+	}
+}
+
+func TestCompareNatural(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"V2", "V10", -1},
+		{"V10", "V2", 1},
+		{"Scene 2", "Scene 10", -1},
+		{"A", "B", -1},
+		{"V1", "V1", 0},
+		{"V", "V1", -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"_"+tt.b, func(t *testing.T) {
+			got := compareNatural(tt.a, tt.b)
+			if (got < 0) != (tt.want < 0) || (got > 0) != (tt.want > 0) {
+				t.Errorf("compareNatural(%q, %q) = %d, want sign of %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
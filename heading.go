@@ -0,0 +1,100 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package ale
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/Avalanche-io/gotio"
+)
+
+// DefaultTimelineName is the name of a decoded timeline unless
+// WithTimelineName or WithTimelineNameFromFile is used
+const DefaultTimelineName = "ALE Timeline"
+
+// HeaderFilmFormat is the heading key for the film format
+const HeaderFilmFormat = "FILM_FORMAT"
+
+// headingOrder lists the standard heading keys in the order they are
+// written; other keys follow alphabetically
+var headingOrder = []string{
+	HeaderFieldDelim,
+	HeaderVideoFormat,
+	HeaderAudioFormat,
+	HeaderFilmFormat,
+	HeaderFPS,
+}
+
+// timelineName returns the configured name for a decoded timeline or bin
+func (d *Decoder) timelineName(fallback string) string {
+	if d.name != "" {
+		return d.name
+	}
+	if d.nameFromFile {
+		if named, ok := d.r.(interface{ Name() string }); ok {
+			base := filepath.Base(named.Name())
+			if name := strings.TrimSuffix(base, filepath.Ext(base)); name != "" {
+				return name
+			}
+		}
+	}
+	return fallback
+}
+
+// headingMetadata stores the heading entries of a file under
+// metadata[key]["Heading"] for the encoder to write back
+func (d *Decoder) headingMetadata(aleFile *ALEFile) gotio.AnyDictionary {
+	if len(aleFile.Headers) == 0 {
+		return nil
+	}
+
+	heading := make(map[string]interface{}, len(aleFile.Headers))
+	for key, value := range aleFile.Headers {
+		heading[key] = value
+	}
+	return gotio.AnyDictionary{
+		d.metadataKey: map[string]interface{}{
+			HeaderHeading: heading,
+		},
+	}
+}
+
+// headingValues returns the heading entries stored in timeline or
+// collection metadata by the decoder
+func (e *Encoder) headingValues(metadata gotio.AnyDictionary) map[string]string {
+	heading := asMap(asMap(metadata[e.metadataKey])[HeaderHeading])
+	if len(heading) == 0 {
+		return nil
+	}
+
+	values := make(map[string]string, len(heading))
+	for key, value := range heading {
+		if s, ok := value.(string); ok {
+			values[key] = s
+		}
+	}
+	return values
+}
+
+// sortedHeadingKeys returns heading keys with the standard keys first
+func sortedHeadingKeys(headers map[string]string) []string {
+	var keys []string
+	for _, key := range headingOrder {
+		if _, ok := headers[key]; ok {
+			keys = append(keys, key)
+		}
+	}
+
+	var rest []string
+	for key := range headers {
+		if !slices.Contains(headingOrder, key) {
+			rest = append(rest, key)
+		}
+	}
+	slices.Sort(rest)
+
+	return append(keys, rest...)
+}
@@ -0,0 +1,172 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package ale
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+)
+
+const headingALE = `Heading
+FIELD_DELIM	TABS
+VIDEO_FORMAT	720
+AUDIO_FORMAT	96khz
+FILM_FORMAT	35mm, 4 perf
+TAPE	DAY01
+FPS	25
+
+Column
+Name	Start	End
+
+Data
+B	10:00:05:00	10:00:06:00
+A	10:00:01:00	10:00:02:00
+`
+
+func TestDecoder_HeadingMetadata(t *testing.T) {
+	timeline, err := NewDecoder(strings.NewReader(headingALE)).Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode ALE: %v", err)
+	}
+
+	heading, ok := asMap(timeline.Metadata()[DefaultMetadataKey])[HeaderHeading].(map[string]interface{})
+	if !ok {
+		t.Fatal("Missing heading metadata")
+	}
+	want := map[string]string{
+		HeaderVideoFormat: "720",
+		HeaderAudioFormat: "96khz",
+		HeaderFilmFormat:  "35mm, 4 perf",
+		"TAPE":            "DAY01",
+		HeaderFPS:         "25",
+	}
+	for key, value := range want {
+		if heading[key] != value {
+			t.Errorf("Heading %s = %v, want %q", key, heading[key], value)
+		}
+	}
+
+	// The encoder writes the heading back in a fixed order
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf, WithEncoderFPS(25)).Encode(timeline); err != nil {
+		t.Fatalf("Failed to encode timeline: %v", err)
+	}
+
+	wantHeading := "Heading\nFIELD_DELIM\tTABS\nVIDEO_FORMAT\t720\nAUDIO_FORMAT\t96khz\nFILM_FORMAT\t35mm, 4 perf\nFPS\t25.00\nTAPE\tDAY01\n\n"
+	if !strings.HasPrefix(buf.String(), wantHeading) {
+		t.Errorf("Heading =\n%s\nwant\n%s", buf.String()[:strings.Index(buf.String(), HeaderColumn)], wantHeading)
+	}
+}
+
+func TestDecoder_MultiPairHeadingLine(t *testing.T) {
+	data, err := os.ReadFile("testdata/sample2.ale")
+	if err != nil {
+		t.Fatalf("Failed to read test file: %v", err)
+	}
+
+	aleFile, err := NewDecoder(bytes.NewReader(data)).parseALE(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to parse ALE: %v", err)
+	}
+
+	want := map[string]string{
+		HeaderFieldDelim:  "TABS",
+		HeaderVideoFormat: "1080",
+		HeaderAudioFormat: "48Khz",
+		HeaderFPS:         "23.98",
+	}
+	for key, value := range want {
+		if aleFile.Headers[key] != value {
+			t.Errorf("Header %s = %q, want %q", key, aleFile.Headers[key], value)
+		}
+	}
+}
+
+func TestEncoder_HeadingFPS(t *testing.T) {
+	// Without WithEncoderFPS, sample2 is written back at its FPS heading
+	data, err := os.ReadFile("testdata/sample2.ale")
+	if err != nil {
+		t.Fatalf("Failed to read test file: %v", err)
+	}
+	timeline, err := NewDecoder(bytes.NewReader(data)).Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode ALE: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).Encode(timeline); err != nil {
+		t.Fatalf("Failed to encode timeline: %v", err)
+	}
+	aleFile, err := ReadALE(&buf)
+	if err != nil {
+		t.Fatalf("Failed to read encoded ALE: %v", err)
+	}
+	if got := aleFile.Headers[HeaderFPS]; got != "23.98" {
+		t.Errorf("FPS heading = %q, want 23.98", got)
+	}
+	row := aleFile.Row(0)
+	if got := row.Get(ColumnStart); got != "04:00:00:00" {
+		t.Errorf("Start = %q, want 04:00:00:00", got)
+	}
+	if got := row.Get(ColumnEnd); got != "04:00:46:16" {
+		t.Errorf("End = %q, want 04:00:46:16", got)
+	}
+}
+
+func TestDecoder_TimelineName(t *testing.T) {
+	timeline, err := NewDecoder(strings.NewReader(headingALE)).Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode ALE: %v", err)
+	}
+	if timeline.Name() != DefaultTimelineName {
+		t.Errorf("Timeline name = %q, want %q", timeline.Name(), DefaultTimelineName)
+	}
+
+	timeline, err = NewDecoder(strings.NewReader(headingALE), WithTimelineName("Day 1 Dailies")).Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode ALE: %v", err)
+	}
+	if timeline.Name() != "Day 1 Dailies" {
+		t.Errorf("Timeline name = %q, want %q", timeline.Name(), "Day 1 Dailies")
+	}
+
+	file, err := os.Open("testdata/sample_cdl.ale")
+	if err != nil {
+		t.Fatalf("Failed to open test file: %v", err)
+	}
+	defer file.Close()
+
+	timeline, err = NewDecoder(file, WithTimelineNameFromFile()).Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode ALE: %v", err)
+	}
+	if timeline.Name() != "sample_cdl" {
+		t.Errorf("Timeline name = %q, want %q", timeline.Name(), "sample_cdl")
+	}
+}
+
+func TestDecoder_WithGlobalStartTime(t *testing.T) {
+	timeline, err := NewDecoder(strings.NewReader(headingALE)).Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode ALE: %v", err)
+	}
+	if timeline.GlobalStartTime() != nil {
+		t.Error("Global start time should not be set by default")
+	}
+
+	timeline, err = NewDecoder(strings.NewReader(headingALE), WithGlobalStartTime(true)).Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode ALE: %v", err)
+	}
+	start := timeline.GlobalStartTime()
+	if start == nil {
+		t.Fatal("Global start time not set")
+	}
+	if tc, _ := formatTimecode(*start, 25, false); tc != "10:00:01:00" {
+		t.Errorf("Global start time = %s, want 10:00:01:00", tc)
+	}
+}
@@ -0,0 +1,153 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package ale
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/Avalanche-io/gotio"
+	"github.com/Avalanche-io/gotio/opentime"
+)
+
+// TimelineLayout controls how decoded clips are placed on their tracks
+type TimelineLayout int
+
+// Timeline layouts
+const (
+	// TimelineSequential appends clips back-to-back in row order
+	TimelineSequential TimelineLayout = iota
+	// TimelineTimecodeSync places clips at their Start timecode relative to
+	// the earliest start, with gaps in between. Overlapping clips are pushed
+	// to additional tracks.
+	TimelineTimecodeSync
+)
+
+// trackGroup holds the clips assigned to one track key
+type trackGroup struct {
+	kind  string
+	clips []*gotio.Clip
+}
+
+// lane is one track of a timecode-synchronous group and the frame where
+// its last clip ends
+type lane struct {
+	track *gotio.Track
+	end   float64
+}
+
+// layoutTracks builds the tracks for a group of clips. Sequential layout
+// yields a single track; timecode-synchronous layout yields one track per
+// lane of overlapping clips, named "Key", "Key 2", "Key 3", ...
+func (d *Decoder) layoutTracks(key string, group *trackGroup, origin float64) ([]*gotio.Track, error) {
+	if d.timeline != TimelineTimecodeSync {
+		track := gotio.NewTrack(key, nil, group.kind, nil, nil)
+		for _, clip := range group.clips {
+			if err := track.AppendChild(d.item(clip)); err != nil {
+				return nil, fmt.Errorf("failed to append clip to track: %w", err)
+			}
+		}
+		return []*gotio.Track{track}, nil
+	}
+
+	// Place clips in start order; clips without a source range go last
+	clips := slices.Clone(group.clips)
+	slices.SortStableFunc(clips, func(a, b *gotio.Clip) int {
+		aStart, aOk := clipStartFrame(a, d.fps)
+		bStart, bOk := clipStartFrame(b, d.fps)
+		switch {
+		case aOk && bOk:
+			return cmp.Compare(aStart, bStart)
+		case aOk:
+			return -1
+		case bOk:
+			return 1
+		}
+		return 0
+	})
+
+	var lanes []*lane
+	for _, clip := range clips {
+		start, ok := clipStartFrame(clip, d.fps)
+		if !ok {
+			// Nothing to sync to, append after the first lane's last clip
+			if len(lanes) == 0 {
+				lanes = append(lanes, &lane{track: gotio.NewTrack(key, nil, group.kind, nil, nil), end: origin})
+			}
+			if err := lanes[0].track.AppendChild(d.item(clip)); err != nil {
+				return nil, fmt.Errorf("failed to append clip to track: %w", err)
+			}
+			continue
+		}
+
+		// Use the first lane that is free at the clip's start
+		var target *lane
+		for _, l := range lanes {
+			if l.end <= start {
+				target = l
+				break
+			}
+		}
+		if target == nil {
+			name := key
+			if len(lanes) > 0 {
+				name = fmt.Sprintf("%s %d", key, len(lanes)+1)
+			}
+			target = &lane{track: gotio.NewTrack(name, nil, group.kind, nil, nil), end: origin}
+			lanes = append(lanes, target)
+		}
+
+		if gap := start - target.end; gap > 0 {
+			if err := target.track.AppendChild(gotio.NewGapWithDuration(opentime.NewRationalTime(gap, d.fps))); err != nil {
+				return nil, fmt.Errorf("failed to append gap to track: %w", err)
+			}
+		}
+		if err := target.track.AppendChild(d.item(clip)); err != nil {
+			return nil, fmt.Errorf("failed to append clip to track: %w", err)
+		}
+		target.end = start + clip.SourceRange().Duration().RescaledTo(d.fps).Value()
+	}
+
+	tracks := make([]*gotio.Track, len(lanes))
+	for i, l := range lanes {
+		tracks[i] = l.track
+	}
+	return tracks, nil
+}
+
+// clipStartFrame returns a clip's start timecode in frames at fps
+func clipStartFrame(clip *gotio.Clip, fps float64) (float64, bool) {
+	sourceRange := clip.SourceRange()
+	if sourceRange == nil {
+		return 0, false
+	}
+	return sourceRange.StartTime().RescaledTo(fps).Value(), true
+}
+
+// hasSourceRange reports whether any clip has a source range
+func hasSourceRange(clips []*gotio.Clip) bool {
+	for _, clip := range clips {
+		if clip.SourceRange() != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// earliestStart returns the earliest clip start in frames at fps, or 0 if
+// no clip has a source range
+func earliestStart(clips []*gotio.Clip, fps float64) float64 {
+	earliest := math.Inf(1)
+	for _, clip := range clips {
+		if start, ok := clipStartFrame(clip, fps); ok && start < earliest {
+			earliest = start
+		}
+	}
+	if math.IsInf(earliest, 1) {
+		return 0
+	}
+	return earliest
+}
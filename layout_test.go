@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package ale
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Avalanche-io/gotio"
+)
+
+const syncALE = `Heading
+FIELD_DELIM	TABS
+FPS	24
+
+Column
+Name	Camera	Start	End
+
+Data
+A2	A	01:00:10:00	01:00:20:00
+A1	A	01:00:00:00	01:00:05:00
+A3	A	01:00:15:00	01:00:25:00
+B1	B	01:00:02:00	01:00:12:00
+`
+
+// describeTrack lists a track's children as "name" for clips and
+// "gap:frames" for gaps
+func describeTrack(track *gotio.Track) string {
+	var parts []string
+	for _, child := range track.Children() {
+		switch item := child.(type) {
+		case *gotio.Clip:
+			parts = append(parts, item.Name())
+		case *gotio.Gap:
+			parts = append(parts, "gap:"+formatFrameNumber(item.SourceRange().Duration(), 24))
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+func TestDecoder_TimecodeSyncLayout(t *testing.T) {
+	decoder := NewDecoder(strings.NewReader(syncALE), WithTrackGrouping(GroupByCamera), WithTimelineLayout(TimelineTimecodeSync))
+	timeline, err := decoder.Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode ALE: %v", err)
+	}
+
+	want := map[string]string{
+		"Camera A":   "A1,gap:120,A2",
+		"Camera A 2": "gap:360,A3",
+		"Camera B":   "gap:48,B1",
+	}
+
+	tracks := timeline.Tracks().Children()
+	if len(tracks) != len(want) {
+		t.Fatalf("Expected %d tracks, got %v", len(want), trackNames(timeline))
+	}
+	for i, name := range []string{"Camera A", "Camera A 2", "Camera B"} {
+		track := tracks[i].(*gotio.Track)
+		if track.Name() != name {
+			t.Errorf("Track %d name = %q, want %q", i, track.Name(), name)
+			continue
+		}
+		if got := describeTrack(track); got != want[name] {
+			t.Errorf("Track %q = %s, want %s", name, got, want[name])
+		}
+	}
+}
+
+func TestDecoder_SequentialLayoutDefault(t *testing.T) {
+	timeline, err := NewDecoder(strings.NewReader(syncALE)).Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode ALE: %v", err)
+	}
+
+	tracks := timeline.Tracks().Children()
+	if len(tracks) != 1 {
+		t.Fatalf("Expected 1 track, got %d", len(tracks))
+	}
+	if got := describeTrack(tracks[0].(*gotio.Track)); got != "A2,A1,A3,B1" {
+		t.Errorf("Track = %s, want clips in row order", got)
+	}
+}
@@ -0,0 +1,220 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package ale
+
+import (
+	"strings"
+
+	"github.com/Avalanche-io/gotio"
+)
+
+// Default metadata keys
+const (
+	DefaultMetadataKey    = "ALE"
+	DefaultCDLMetadataKey = "cdl"
+)
+
+// MetadataLayout controls how column values are arranged under the
+// metadata key
+type MetadataLayout int
+
+// Metadata layouts
+const (
+	// LayoutFlat stores columns directly: metadata[key][column]
+	LayoutFlat MetadataLayout = iota
+	// LayoutByCategory nests columns by category: metadata[key][category][column]
+	LayoutByCategory
+)
+
+// Column categories used by LayoutByCategory
+const (
+	CategoryTimecode = "timecode"
+	CategoryFilm     = "film"
+	CategoryCamera   = "camera"
+	CategoryColor    = "color"
+	CategoryGeneral  = "general"
+)
+
+// columnCategories assigns the standard Avid columns to categories
+var columnCategories = map[string]string{
+	"Mark IN":          CategoryTimecode,
+	"Mark OUT":         CategoryTimecode,
+	"IN-OUT":           CategoryTimecode,
+	"VITC":             CategoryTimecode,
+	"Original_Start":   CategoryTimecode,
+	"Original_End":     CategoryTimecode,
+	"Frame":            CategoryFilm,
+	"Perf":             CategoryFilm,
+	"Pullin":           CategoryFilm,
+	"Pullout":          CategoryFilm,
+	"Cadence":          CategoryFilm,
+	"Labroll":          CategoryFilm,
+	"DPX":              CategoryFilm,
+	"Camera":           CategoryCamera,
+	"Camroll":          CategoryCamera,
+	"CFPS":             CategoryCamera,
+	"Shoot Date":       CategoryCamera,
+	"Scene":            CategoryCamera,
+	"Take":             CategoryCamera,
+	"Orig_Camera":      CategoryCamera,
+	"Image Size":       CategoryCamera,
+	"Raster Dimension": CategoryCamera,
+	"LUT":              CategoryColor,
+	"CDL":              CategoryColor,
+	"ColorCorr id":     CategoryColor,
+	"Color Space":      CategoryColor,
+}
+
+// columnCategory returns the category of a column, falling back to name
+// patterns for columns not listed in columnCategories
+func columnCategory(column string) string {
+	if category, ok := columnCategories[column]; ok {
+		return category
+	}
+
+	upper := strings.ToUpper(column)
+	switch {
+	case strings.HasPrefix(upper, "ASC_") || strings.HasPrefix(upper, "COLOR "):
+		return CategoryColor
+	case hasTimecodeWord(upper):
+		return CategoryTimecode
+	case strings.HasPrefix(upper, "KN ") || strings.HasPrefix(upper, "INK ") ||
+		strings.HasPrefix(upper, "AUXINK ") || strings.HasPrefix(upper, "AUXILIARY INK") ||
+		strings.Contains(upper, "FILM") || strings.HasPrefix(upper, "MASTER "):
+		return CategoryFilm
+	case strings.HasPrefix(upper, "S3D ") || strings.HasPrefix(upper, "CAMERA"):
+		return CategoryCamera
+	}
+
+	return CategoryGeneral
+}
+
+// hasTimecodeWord reports whether an upper-cased column name contains a
+// timecode word such as "TC", "TC1" or "TIMECODE"
+func hasTimecodeWord(upper string) bool {
+	for _, word := range strings.Fields(upper) {
+		if strings.HasPrefix(word, "TC") || word == "TIMECODE" {
+			return true
+		}
+	}
+	return false
+}
+
+// layoutColumns arranges flat column values in the given layout
+func layoutColumns(columns map[string]interface{}, layout MetadataLayout) map[string]interface{} {
+	if layout != LayoutByCategory {
+		return columns
+	}
+
+	nested := make(map[string]interface{})
+	for col, value := range columns {
+		category := columnCategory(col)
+		group, ok := nested[category].(map[string]interface{})
+		if !ok {
+			group = make(map[string]interface{})
+			nested[category] = group
+		}
+		group[col] = value
+	}
+	return nested
+}
+
+// flattenColumns reads column values stored in the given layout back into
+// a flat map, leaving out the structured values
+func flattenColumns(value interface{}, layout MetadataLayout) map[string]interface{} {
+	columns := asMap(value)
+	if columns == nil {
+		return nil
+	}
+
+	flat := make(map[string]interface{}, len(columns))
+	for key, v := range columns {
+		if structuredFields[key] {
+			continue
+		}
+		if layout != LayoutByCategory {
+			flat[key] = v
+			continue
+		}
+		for col, v := range asMap(v) {
+			flat[col] = v
+		}
+	}
+	return flat
+}
+
+// asMap returns a metadata value as a map, accepting both plain maps and
+// gotio dictionaries
+func asMap(value interface{}) map[string]interface{} {
+	if m, ok := value.(map[string]interface{}); ok {
+		return m
+	}
+	if m, ok := value.(gotio.AnyDictionary); ok {
+		return map[string]interface{}(m)
+	}
+	return nil
+}
+
+// Keys of the structured values stored under the metadata key beside the
+// decoded columns
+const (
+	datesField    = "_dates"
+	pulldownField = "_pulldown"
+	stereoField   = "_stereo"
+	geometryField = "_geometry"
+	colorField    = "_color"
+	multicamField = "_multicam"
+	rawField      = "_raw"
+	// cdlField lists the columns a clip's grade was read from
+	cdlField = "_cdl"
+	// columnsField maps clip properties to the logged columns that
+	// filled them, where those are not the canonical columns
+	columnsField = "_columns"
+)
+
+// structuredFields holds the keys flattenColumns skips
+var structuredFields = map[string]bool{
+	datesField:    true,
+	pulldownField: true,
+	stereoField:   true,
+	geometryField: true,
+	colorField:    true,
+	multicamField: true,
+	rawField:      true,
+	cdlField:      true,
+	columnsField:  true,
+}
+
+// structured returns a structured value stored under the metadata key
+func structured(metadata gotio.AnyDictionary, metadataKey, field string) interface{} {
+	return asMap(metadata[metadataKey])[field]
+}
+
+// stringList reads a list of strings, as stored or after a JSON round trip
+func stringList(value interface{}) []string {
+	switch v := value.(type) {
+	case []string:
+		return v
+	case []interface{}:
+		var list []string
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				list = append(list, s)
+			}
+		}
+		return list
+	}
+	return nil
+}
+
+// setStructured stores a structured value under the metadata key,
+// creating it if the clip has no columns
+func setStructured(metadata gotio.AnyDictionary, metadataKey, field string, value interface{}) {
+	namespace := asMap(metadata[metadataKey])
+	if namespace == nil {
+		namespace = make(map[string]interface{})
+		metadata[metadataKey] = namespace
+	}
+	namespace[field] = value
+}
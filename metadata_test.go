@@ -0,0 +1,137 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package ale
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestColumnCategory(t *testing.T) {
+	tests := []struct {
+		column string
+		want   string
+	}{
+		{"Auxiliary TC1", CategoryTimecode},
+		{"TC 24", CategoryTimecode},
+		{"Sound TC", CategoryTimecode},
+		{"KN Start", CategoryFilm},
+		{"Ink Number", CategoryFilm},
+		{"Pullin", CategoryFilm},
+		{"Camera", CategoryCamera},
+		{"Camroll", CategoryCamera},
+		{"S3D Channel", CategoryCamera},
+		{"ASC_SOP", CategoryColor},
+		{"Color Space", CategoryColor},
+		{"LUT", CategoryColor},
+		{"Project", CategoryGeneral},
+		{"Match Frame", CategoryGeneral},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.column, func(t *testing.T) {
+			if got := columnCategory(tt.column); got != tt.want {
+				t.Errorf("columnCategory(%q) = %q, want %q", tt.column, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecoder_WithMetadataKey(t *testing.T) {
+	data, err := os.ReadFile("testdata/sample_cdl.ale")
+	if err != nil {
+		t.Fatalf("Failed to read test file: %v", err)
+	}
+
+	decoder := NewDecoder(bytes.NewReader(data), WithMetadataKey("avid"), WithCDLMetadataKey("grade"))
+	timeline, err := decoder.Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode ALE: %v", err)
+	}
+
+	metadata := timeline.FindClips(nil, false)[0].Metadata()
+	if _, ok := metadata["ALE"]; ok {
+		t.Error("Columns should not be stored under ALE")
+	}
+	if _, ok := metadata["avid"].(map[string]interface{}); !ok {
+		t.Error("Missing columns under avid")
+	}
+	if _, ok := asMap(metadata["avid"])["_dates"].(map[string]interface{}); !ok {
+		t.Error("Missing dates under avid")
+	}
+	if _, ok := metadata["grade"].(*CDLData); !ok {
+		t.Error("Missing CDL under grade")
+	}
+	for key := range metadata {
+		if key != "avid" && key != "grade" {
+			t.Errorf("Unexpected top-level metadata key %q", key)
+		}
+	}
+
+	// The encoder reads the same keys back
+	var buf bytes.Buffer
+	encoder := NewEncoder(&buf, WithEncoderMetadataKey("avid"), WithEncoderCDLMetadataKey("grade"))
+	if err := encoder.Encode(timeline); err != nil {
+		t.Fatalf("Failed to encode timeline: %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{"ASC_SOP", "Shoot Date", "20190501", "R3D.ToneMap"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Output missing %q", want)
+		}
+	}
+}
+
+func TestDecoder_WithMetadataLayoutByCategory(t *testing.T) {
+	data, err := os.ReadFile("testdata/sample2.ale")
+	if err != nil {
+		t.Fatalf("Failed to read test file: %v", err)
+	}
+
+	decoder := NewDecoder(bytes.NewReader(data), WithMetadataLayout(LayoutByCategory))
+	timeline, err := decoder.Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode ALE: %v", err)
+	}
+
+	aleMap := timeline.FindClips(nil, false)[0].Metadata()["ALE"].(map[string]interface{})
+
+	camera, ok := aleMap[CategoryCamera].(map[string]interface{})
+	if !ok {
+		t.Fatal("Missing camera category")
+	}
+	if camera["Scene"] != "19A" {
+		t.Errorf("camera Scene = %v, want 19A", camera["Scene"])
+	}
+
+	timecode, ok := aleMap[CategoryTimecode].(map[string]interface{})
+	if !ok {
+		t.Fatal("Missing timecode category")
+	}
+	if timecode["TC 24"] != "04:00:00:00" {
+		t.Errorf("timecode TC 24 = %v, want 04:00:00:00", timecode["TC 24"])
+	}
+
+	// The encoder flattens the categories back into columns
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf, WithEncoderMetadataLayout(LayoutByCategory)).Encode(timeline); err != nil {
+		t.Fatalf("Failed to encode timeline: %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{"Scene", "TC 24", "Camroll", "XA007"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Output missing %q", want)
+		}
+	}
+	if strings.Contains(output, CategoryCamera) {
+		t.Error("Output should not contain category names")
+	}
+	if strings.Contains(output, datesField) {
+		t.Error("Output should not contain structured metadata keys")
+	}
+}
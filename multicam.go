@@ -0,0 +1,175 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package ale
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/Avalanche-io/gotio"
+	"github.com/Avalanche-io/gotio/opentime"
+)
+
+// ColumnMulticamGroup is the column the encoder writes multicam group names to
+const ColumnMulticamGroup = "Multicam Group"
+
+// multicamGroup is a set of clips from different cameras with overlapping
+// timecode
+type multicamGroup struct {
+	name    string
+	start   float64
+	end     float64
+	clips   []*gotio.Clip
+	cameras []string
+}
+
+// multicamCamera returns the camera of a row, from Camera or Camroll
+func multicamCamera(row Row) string {
+	if camera := row.Get(ColumnCamera); camera != "" {
+		return camera
+	}
+	return row.Get("Camroll")
+}
+
+// multicamTrack groups clips by overlapping timecode and camera and returns
+// a "Multicam" track holding one Stack per group. Clips without timecode
+// are appended after the groups.
+func (d *Decoder) multicamTrack(clips []*gotio.Clip, cameras []string, origin float64) (*gotio.Track, error) {
+	order := make([]int, 0, len(clips))
+	var ungrouped []*gotio.Clip
+	for i, clip := range clips {
+		if _, ok := clipStartFrame(clip, d.fps); ok {
+			order = append(order, i)
+		} else {
+			ungrouped = append(ungrouped, clip)
+		}
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		aStart, _ := clipStartFrame(clips[a], d.fps)
+		bStart, _ := clipStartFrame(clips[b], d.fps)
+		return cmp.Compare(aStart, bStart)
+	})
+
+	// A clip joins the current group if it overlaps it, within the
+	// tolerance, and its camera is not already in the group
+	var groups []*multicamGroup
+	for _, i := range order {
+		clip, camera := clips[i], cameras[i]
+		start, _ := clipStartFrame(clip, d.fps)
+		end := start + clip.SourceRange().Duration().RescaledTo(d.fps).Value()
+
+		var group *multicamGroup
+		if len(groups) > 0 {
+			group = groups[len(groups)-1]
+			if start >= group.end+d.multicamTol || slices.Contains(group.cameras, camera) {
+				group = nil
+			}
+		}
+		if group == nil {
+			group = &multicamGroup{
+				name:  fmt.Sprintf("Group %d", len(groups)+1),
+				start: start,
+				end:   end,
+			}
+			groups = append(groups, group)
+		}
+
+		group.clips = append(group.clips, clip)
+		group.cameras = append(group.cameras, camera)
+		group.end = max(group.end, end)
+	}
+
+	track := gotio.NewTrack("Multicam", nil, gotio.TrackKindVideo, nil, nil)
+	position := origin
+	for _, group := range groups {
+		if gap := group.start - position; d.timeline == TimelineTimecodeSync && gap > 0 {
+			if err := track.AppendChild(gotio.NewGapWithDuration(opentime.NewRationalTime(gap, d.fps))); err != nil {
+				return nil, fmt.Errorf("failed to append gap to track: %w", err)
+			}
+		}
+
+		stack, err := d.multicamStack(group)
+		if err != nil {
+			return nil, err
+		}
+		if err := track.AppendChild(stack); err != nil {
+			return nil, fmt.Errorf("failed to append multicam group to track: %w", err)
+		}
+		position = group.end
+	}
+
+	for _, clip := range ungrouped {
+		if err := track.AppendChild(d.item(clip)); err != nil {
+			return nil, fmt.Errorf("failed to append clip to track: %w", err)
+		}
+	}
+
+	return track, nil
+}
+
+// multicamStack builds a Stack with one track per camera, each clip offset
+// from the start of the group by a gap
+func (d *Decoder) multicamStack(group *multicamGroup) (*gotio.Stack, error) {
+	cameras := slices.Clone(group.cameras)
+	slices.SortFunc(cameras, compareNatural)
+	cameraNames := make([]interface{}, len(cameras))
+	for i, camera := range cameras {
+		cameraNames[i] = camera
+	}
+
+	stack := gotio.NewStack(
+		group.name,
+		nil,
+		gotio.AnyDictionary{
+			d.metadataKey: map[string]interface{}{
+				multicamField: map[string]interface{}{
+					"group":   group.name,
+					"cameras": cameraNames,
+				},
+			},
+		},
+		nil,
+		nil,
+		nil,
+	)
+
+	for _, camera := range cameras {
+		i := slices.Index(group.cameras, camera)
+		clip := group.clips[i]
+
+		trackName := camera
+		if trackName == "" {
+			trackName = ColumnCamera
+		}
+		track := gotio.NewTrack(trackName, nil, gotio.TrackKindVideo, nil, nil)
+
+		start, _ := clipStartFrame(clip, d.fps)
+		if gap := start - group.start; gap > 0 {
+			if err := track.AppendChild(gotio.NewGapWithDuration(opentime.NewRationalTime(gap, d.fps))); err != nil {
+				return nil, fmt.Errorf("failed to append gap to track: %w", err)
+			}
+		}
+		if err := track.AppendChild(d.item(clip)); err != nil {
+			return nil, fmt.Errorf("failed to append clip to track: %w", err)
+		}
+
+		setStructured(clip.Metadata(), d.metadataKey, multicamField, map[string]interface{}{
+			"group":  group.name,
+			"camera": camera,
+		})
+
+		if err := stack.AppendChild(track); err != nil {
+			return nil, fmt.Errorf("failed to append camera track to multicam group: %w", err)
+		}
+	}
+
+	return stack, nil
+}
+
+// multicamGroupName returns the multicam group a clip was decoded into
+func multicamGroupName(metadata gotio.AnyDictionary, metadataKey string) (string, bool) {
+	group, ok := asMap(structured(metadata, metadataKey, multicamField))["group"].(string)
+	return group, ok
+}
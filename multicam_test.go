@@ -0,0 +1,118 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package ale
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Avalanche-io/gotio"
+)
+
+const multicamALE = `Heading
+FIELD_DELIM	TABS
+FPS	24
+
+Column
+Name	Camera	Start	End
+
+Data
+A1	A	01:00:00:00	01:00:10:00
+B1	B	01:00:01:00	01:00:10:00
+C1	C	01:00:10:02	01:00:20:00
+A2	A	01:01:00:00	01:01:10:00
+B2	B	01:01:00:00	01:01:05:00
+`
+
+func TestDecoder_WithMulticamGroups(t *testing.T) {
+	decoder := NewDecoder(strings.NewReader(multicamALE), WithMulticamGroups(4))
+	timeline, err := decoder.Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode ALE: %v", err)
+	}
+
+	tracks := timeline.Tracks().Children()
+	if len(tracks) != 1 {
+		t.Fatalf("Expected 1 track, got %v", trackNames(timeline))
+	}
+	track := tracks[0].(*gotio.Track)
+	if track.Name() != "Multicam" {
+		t.Errorf("Track name = %q, want Multicam", track.Name())
+	}
+
+	// C1 starts 2 frames after the group ends, within the tolerance
+	want := []map[string]string{
+		{"A": "A1", "B": "gap:24,B1", "C": "gap:242,C1"},
+		{"A": "A2", "B": "B2"},
+	}
+
+	groups := track.Children()
+	if len(groups) != len(want) {
+		t.Fatalf("Expected %d groups, got %d", len(want), len(groups))
+	}
+	for i, child := range groups {
+		stack, ok := child.(*gotio.Stack)
+		if !ok {
+			t.Fatalf("Group %d is %T, want *gotio.Stack", i, child)
+		}
+		cameras := stack.Children()
+		if len(cameras) != len(want[i]) {
+			t.Errorf("Group %d has %d cameras, want %d", i, len(cameras), len(want[i]))
+			continue
+		}
+		for _, c := range cameras {
+			camera := c.(*gotio.Track)
+			if got := describeTrack(camera); got != want[i][camera.Name()] {
+				t.Errorf("Group %d camera %q = %s, want %s", i, camera.Name(), got, want[i][camera.Name()])
+			}
+		}
+	}
+
+	// The encoder writes each clip's group
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).Encode(timeline); err != nil {
+		t.Fatalf("Failed to encode timeline: %v", err)
+	}
+
+	output := buf.String()
+	if !strings.Contains(output, ColumnMulticamGroup) {
+		t.Errorf("Output missing %q column", ColumnMulticamGroup)
+	}
+	for _, want := range []string{"Group 1", "Group 2"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Output missing %q", want)
+		}
+	}
+}
+
+func TestDecoder_WithMulticamGroupsSameCamera(t *testing.T) {
+	// Overlapping clips from one camera never share a group
+	input := `Heading
+FIELD_DELIM	TABS
+FPS	24
+
+Column
+Name	Camroll	Start	End
+
+Data
+A1	A001	01:00:00:00	01:00:10:00
+A2	A001	01:00:05:00	01:00:15:00
+`
+	timeline, err := NewDecoder(strings.NewReader(input), WithMulticamGroups(0)).Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode ALE: %v", err)
+	}
+
+	want := map[string]string{"A1": "Group 1", "A2": "Group 2"}
+	for _, clip := range timeline.FindClips(nil, false) {
+		group, ok := multicamGroupName(clip.Metadata(), DefaultMetadataKey)
+		if !ok {
+			t.Fatalf("Clip %q has no multicam group", clip.Name())
+		}
+		if group != want[clip.Name()] {
+			t.Errorf("Clip %q group = %q, want %q", clip.Name(), group, want[clip.Name()])
+		}
+	}
+}
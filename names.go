@@ -0,0 +1,32 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package ale
+
+import (
+	"regexp"
+	"strings"
+)
+
+// namePlaceholder matches a {Column} placeholder in a name template
+var namePlaceholder = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// isNameTemplate reports whether a name source is a template
+func isNameTemplate(source string) bool {
+	return namePlaceholder.MatchString(source)
+}
+
+// expandNameTemplate replaces each {Column} placeholder with the column
+// value. It fails if any referenced column is empty.
+func expandNameTemplate(template string, column func(name string) string) (string, bool) {
+	ok := true
+	name := namePlaceholder.ReplaceAllStringFunc(template, func(placeholder string) string {
+		col := strings.TrimSpace(placeholder[1 : len(placeholder)-1])
+		value := column(col)
+		if value == "" {
+			ok = false
+		}
+		return value
+	})
+	return name, ok
+}
@@ -0,0 +1,281 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package ale
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/Avalanche-io/gotio"
+	"github.com/Avalanche-io/gotio/opentime"
+)
+
+// Pulldown columns
+const (
+	ColumnPullin      = "Pullin"
+	ColumnPullout     = "Pullout"
+	ColumnCadence     = "Cadence"
+	ColumnFieldMotion = "Field Motion"
+)
+
+// Film and video rates related by 2:3 pulldown
+const (
+	FilmFPS  = 24000.0 / 1001.0
+	VideoFPS = 30000.0 / 1001.0
+)
+
+// pulldownPhases names the five video frames of a 2:3 cadence. Film
+// frames A, B, C and D span 2, 3, 2 and 3 fields, so the fields run
+// AA BB BC CD DD; Avid calls the mixed B/C frame X.
+const pulldownPhases = "ABXCD"
+
+// pulldownFilm is the film frame, within a cadence, shown by each video
+// phase (by its first field)
+var pulldownFilm = [5]int{0, 1, 1, 2, 3}
+
+// pulldownVideo is the first video phase showing each film frame of a
+// cadence
+var pulldownVideo = [4]int{0, 1, 3, 4}
+
+// parsePulldownPhase parses a Pullin or Pullout value (A, B, X, C or D)
+func parsePulldownPhase(value string) (int, bool) {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	if len(value) != 1 {
+		return 0, false
+	}
+	i := strings.IndexByte(pulldownPhases, value[0])
+	return i, i >= 0
+}
+
+// isTwoThreeCadence reports whether a Cadence value is 2:3 pulldown. An
+// empty cadence is assumed to be 2:3.
+func isTwoThreeCadence(value string) bool {
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, value)
+	return digits == "" || digits == "23" || digits == "32" || digits == "2323" || digits == "3232"
+}
+
+// isProgressive reports whether a Field Motion value is progressive
+func isProgressive(value string) bool {
+	return strings.HasPrefix(strings.ToLower(strings.TrimSpace(value)), "progressive")
+}
+
+// pulldown relates video and film frame numbers through a 2:3 cadence
+// whose A frame is videoOrigin on video and filmOrigin on film
+type pulldown struct {
+	videoOrigin int
+	filmOrigin  int
+}
+
+// pulldownFromVideo returns the cadence of a video frame with a known
+// phase. Film frames are numbered so that A frames on video frames
+// divisible by 5 fall on film frames divisible by 4.
+func pulldownFromVideo(frame, phase int) pulldown {
+	origin := frame - phase
+	return pulldown{videoOrigin: origin, filmOrigin: floorDiv(4*origin, 5)}
+}
+
+// pulldownFromFilm returns the cadence of a film frame shown first on a
+// video frame with the given phase; the inverse of pulldownFromVideo
+func pulldownFromFilm(frame, phase int) pulldown {
+	origin := frame - pulldownFilm[phase]
+	return pulldown{videoOrigin: floorDiv(5*origin, 4), filmOrigin: origin}
+}
+
+// film returns the film frame shown on a video frame
+func (p pulldown) film(video int) int {
+	offset := video - p.videoOrigin
+	return p.filmOrigin + 4*floorDiv(offset, 5) + pulldownFilm[floorMod(offset, 5)]
+}
+
+// video returns the first video frame showing a film frame
+func (p pulldown) video(film int) int {
+	offset := film - p.filmOrigin
+	return p.videoOrigin + 5*floorDiv(offset, 4) + pulldownVideo[floorMod(offset, 4)]
+}
+
+// phase returns the phase letter of a video frame
+func (p pulldown) phase(video int) string {
+	return string(pulldownPhases[floorMod(video-p.videoOrigin, 5)])
+}
+
+// floorDiv divides rounding toward negative infinity
+func floorDiv(a, b int) int {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
+
+// floorMod returns the remainder of floorDiv, with the sign of b
+func floorMod(a, b int) int {
+	return a - floorDiv(a, b)*b
+}
+
+// pulldownRange converts a row logged with 29.97 video timecode and a
+// Pullin phase to a source range in 23.976 film frames. It returns a nil
+// range for rows without pulldown, and warnings for an unsupported
+// cadence, progressive Field Motion or a Pullout that does not match the
+// cadence.
+func (d *Decoder) pulldownRange(row Row, index int) (*opentime.TimeRange, map[string]interface{}, []error, error) {
+	pullin := row.Get(ColumnPullin)
+	phase, ok := parsePulldownPhase(pullin)
+	if !ok {
+		return nil, nil, nil, nil
+	}
+
+	if cadence := row.Get(ColumnCadence); !isTwoThreeCadence(cadence) {
+		return nil, nil, []error{&CellError{
+			Row:    index,
+			Column: ColumnCadence,
+			Value:  cadence,
+			Type:   TypeEnum,
+			Err:    fmt.Errorf("only 2:3 pulldown is supported"),
+		}}, nil
+	}
+
+	// 2:3 pulldown spreads film frames over interlaced fields
+	if motion := row.Get(ColumnFieldMotion); isProgressive(motion) {
+		return nil, nil, []error{&CellError{
+			Row:    index,
+			Column: ColumnFieldMotion,
+			Value:  motion,
+			Type:   TypeEnum,
+			Err:    fmt.Errorf("2:3 pulldown needs interlaced video"),
+		}}, nil
+	}
+
+	startTC := row.value(d.column(ColumnStart))
+	if startTC == "" {
+		return nil, nil, nil, nil
+	}
+	start, err := parseTimecode(startTC, VideoFPS)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid start timecode '%s': %w", startTC, err)
+	}
+	videoStart := int(math.Round(start.Value()))
+
+	// End is exclusive; fall back to a duration in video frames
+	var videoEnd int
+	if endTC := row.value(d.column(ColumnEnd)); endTC != "" {
+		end, err := parseTimecode(endTC, VideoFPS)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("invalid end timecode '%s': %w", endTC, err)
+		}
+		videoEnd = int(math.Round(end.Value()))
+	} else if durationStr := row.value(d.column(ColumnDuration)); durationStr != "" {
+		duration, err := parseTimecode(durationStr, VideoFPS)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("invalid duration '%s': %w", durationStr, err)
+		}
+		videoEnd = videoStart + int(math.Round(duration.Value()))
+	} else {
+		return nil, nil, nil, nil
+	}
+	if videoEnd <= videoStart {
+		return nil, nil, nil, fmt.Errorf("end timecode is not after start timecode '%s'", startTC)
+	}
+
+	cadence := pulldownFromVideo(videoStart, phase)
+	filmStart := cadence.film(videoStart)
+	filmEnd := cadence.film(videoEnd-1) + 1
+
+	var warnings []error
+	wantPullout := cadence.phase(videoEnd - 1)
+	if pullout := row.Get(ColumnPullout); pullout != "" && !strings.EqualFold(strings.TrimSpace(pullout), wantPullout) {
+		warnings = append(warnings, &CellError{
+			Row:    index,
+			Column: ColumnPullout,
+			Value:  pullout,
+			Type:   TypeEnum,
+			Err:    fmt.Errorf("2:3 cadence from pull-in %s ends on %s", pulldownPhases[phase:phase+1], wantPullout),
+		})
+	}
+
+	sourceRange := opentime.NewTimeRange(
+		opentime.NewRationalTime(float64(filmStart), FilmFPS),
+		opentime.NewRationalTime(float64(filmEnd-filmStart), FilmFPS),
+	)
+	info := map[string]interface{}{
+		"pullin":      pulldownPhases[phase : phase+1],
+		"pullout":     wantPullout,
+		"video_start":  startTC,
+		"video_rate":   VideoFPS,
+		"video_origin": cadence.videoOrigin,
+		"film_origin":  cadence.filmOrigin,
+	}
+	return &sourceRange, info, warnings, nil
+}
+
+// pulldownVideoRange returns the 29.97 video frames showing a clip's film
+// frames, with their Pullin and Pullout phases. Phases decoded with
+// WithPulldown are kept, so a clip starting or ending on the mixed X frame
+// round-trips, as does the decoded cadence; otherwise A frames fall on
+// film frames divisible by 4. 24-based frame numbers are film frames.
+func (e *Encoder) pulldownVideoRange(sourceRange *opentime.TimeRange, metadata gotio.AnyDictionary) (start, end int, pullin, pullout string) {
+	filmStart := filmFrames(sourceRange.StartTime())
+	filmEnd := filmStart + filmFrames(sourceRange.Duration())
+	if filmEnd <= filmStart {
+		filmEnd = filmStart + 1
+	}
+
+	decoded := asMap(structured(metadata, e.metadataKey, pulldownField))
+	decodedPullin, hasPullin := parsePulldownPhase(stringValue(decoded["pullin"]))
+	cadence, ok := decodedCadence(decoded)
+	if !ok {
+		phase := pulldownVideo[floorMod(filmStart, 4)]
+		if hasPullin {
+			phase = decodedPullin
+		}
+		cadence = pulldownFromFilm(filmStart, phase)
+	}
+
+	// Start on the decoded Pullin when it shows the first film frame
+	first := filmStart - cadence.filmOrigin
+	start = cadence.video(filmStart)
+	if hasPullin && pulldownFilm[decodedPullin] == floorMod(first, 4) {
+		start = cadence.videoOrigin + 5*floorDiv(first, 4) + decodedPullin
+	}
+	end = cadence.video(filmEnd)
+
+	// End on the decoded Pullout when it shows the last film frame
+	last := filmEnd - 1 - cadence.filmOrigin
+	if p, ok := parsePulldownPhase(stringValue(decoded["pullout"])); ok && pulldownFilm[p] == floorMod(last, 4) {
+		end = cadence.videoOrigin + 5*floorDiv(last, 4) + p + 1
+	}
+
+	return start, end, cadence.phase(start), cadence.phase(end - 1)
+}
+
+// decodedCadence returns the cadence stored by the decoder, which keeps
+// the video origin that film frame numbers alone cannot recover
+func decodedCadence(decoded map[string]interface{}) (pulldown, bool) {
+	video, okVideo := floatValue(decoded["video_origin"])
+	film, okFilm := floatValue(decoded["film_origin"])
+	if !okVideo || !okFilm {
+		return pulldown{}, false
+	}
+	return pulldown{videoOrigin: int(math.Round(video)), filmOrigin: int(math.Round(film))}, true
+}
+
+// filmFrames returns a time as film frames, taking 24-based frame numbers
+// as they are and rescaling other rates
+func filmFrames(t opentime.RationalTime) int {
+	if math.Round(t.Rate()) == 24 {
+		return int(math.Round(t.Value()))
+	}
+	return int(math.Round(t.RescaledTo(FilmFPS).Value()))
+}
+
+// stringValue returns a metadata value if it is a string
+func stringValue(value interface{}) string {
+	s, _ := value.(string)
+	return s
+}
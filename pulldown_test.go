@@ -0,0 +1,257 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package ale
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+const pulldownALE = `Heading
+FIELD_DELIM	TABS
+FPS	29.97
+
+Column
+Name	Start	End	Pullin	Pullout
+
+Data
+A001	01:00:00:00	01:00:00:10	A	D
+A002	01:00:00:12	01:00:00:17	X	C
+`
+
+func TestPulldownCadence(t *testing.T) {
+	cadence := pulldownFromVideo(0, 0)
+
+	wantFilm := []int{0, 1, 1, 2, 3, 4, 5, 5, 6, 7}
+	for video, want := range wantFilm {
+		if got := cadence.film(video); got != want {
+			t.Errorf("film(%d) = %d, want %d", video, got, want)
+		}
+	}
+
+	wantVideo := []int{0, 1, 3, 4, 5, 6, 8, 9}
+	for film, want := range wantVideo {
+		if got := cadence.video(film); got != want {
+			t.Errorf("video(%d) = %d, want %d", film, got, want)
+		}
+	}
+
+	if got := cadence.phase(7); got != "X" {
+		t.Errorf("phase(7) = %s, want X", got)
+	}
+
+	// Negative frames use the same cadence
+	if got := cadence.film(-1); got != -1 {
+		t.Errorf("film(-1) = %d, want -1", got)
+	}
+}
+
+func TestDecoder_WithPulldown(t *testing.T) {
+	decoder := NewDecoder(strings.NewReader(pulldownALE), WithPulldown(true))
+	timeline, err := decoder.Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode ALE: %v", err)
+	}
+
+	want := []struct {
+		start    string
+		duration float64
+	}{
+		{"01:00:00:00", 8},
+		{"01:00:00:09", 5},
+	}
+	clips := timeline.FindClips(nil, false)
+	if len(clips) != len(want) {
+		t.Fatalf("Expected %d clips, got %d", len(want), len(clips))
+	}
+	for i, clip := range clips {
+		sourceRange := clip.SourceRange()
+		if rate := sourceRange.StartTime().Rate(); rate != FilmFPS {
+			t.Errorf("Clip %d rate = %v, want %v", i, rate, FilmFPS)
+		}
+		if tc, _ := formatTimecode(sourceRange.StartTime(), FilmFPS, false); tc != want[i].start {
+			t.Errorf("Clip %d film start = %s, want %s", i, tc, want[i].start)
+		}
+		if got := sourceRange.Duration().Value(); got != want[i].duration {
+			t.Errorf("Clip %d duration = %v film frames, want %v", i, got, want[i].duration)
+		}
+	}
+
+	// A002 ends on B, not the logged C
+	warnings := decoder.Warnings()
+	if len(warnings) != 1 {
+		t.Fatalf("Expected 1 warning, got %v", warnings)
+	}
+	var cellErr *CellError
+	if !errors.As(warnings[0], &cellErr) || cellErr.Column != ColumnPullout || cellErr.Row != 1 {
+		t.Errorf("Warning = %v, want a Pullout error on row 1", warnings[0])
+	}
+
+	// The encoder writes the 30-based video timecode back
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf, WithEncoderFPS(FilmFPS), WithEncoderPulldown(true)).Encode(timeline); err != nil {
+		t.Fatalf("Failed to encode timeline: %v", err)
+	}
+
+	roundTrip, err := NewDecoder(bytes.NewReader(buf.Bytes())).parseALE(t.Context())
+	if err != nil {
+		t.Fatalf("Failed to parse encoded ALE: %v", err)
+	}
+	if got := roundTrip.Headers[HeaderFPS]; got != "29.97" {
+		t.Errorf("FPS heading = %s, want 29.97", got)
+	}
+	wantRows := []map[string]string{
+		{ColumnStart: "01:00:00:00", ColumnEnd: "01:00:00:10", ColumnDuration: "10", ColumnPullin: "A", ColumnPullout: "D"},
+		{ColumnStart: "01:00:00:12", ColumnEnd: "01:00:00:17", ColumnDuration: "5", ColumnPullin: "X", ColumnPullout: "B"},
+	}
+	for i, row := range roundTrip.RowMaps() {
+		for col, value := range wantRows[i] {
+			if row[col] != value {
+				t.Errorf("Row %d %s = %q, want %q", i, col, row[col], value)
+			}
+		}
+	}
+}
+
+func TestEncoder_WithEncoderPulldownFromFilm(t *testing.T) {
+	// A 24-based timeline without decoded phases puts A frames on film
+	// frames divisible by 4
+	input := "Heading\nFIELD_DELIM\tTABS\nFPS\t23.976\n\nColumn\nName\tStart\tEnd\n\nData\nA001\t01:00:00:02\t01:00:00:06\n"
+	timeline, err := NewDecoder(strings.NewReader(input)).Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode ALE: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf, WithEncoderFPS(FilmFPS), WithEncoderPulldown(true)).Encode(timeline); err != nil {
+		t.Fatalf("Failed to encode timeline: %v", err)
+	}
+
+	aleFile, err := NewDecoder(bytes.NewReader(buf.Bytes())).parseALE(t.Context())
+	if err != nil {
+		t.Fatalf("Failed to parse encoded ALE: %v", err)
+	}
+	row := aleFile.Row(0)
+	// Film frames C D A B start on video phase C and end on the X frame
+	want := map[string]string{ColumnStart: "01:00:00:03", ColumnEnd: "01:00:00:08", ColumnPullin: "C", ColumnPullout: "X"}
+	for col, value := range want {
+		if got := row.Get(col); got != value {
+			t.Errorf("%s = %q, want %q", col, got, value)
+		}
+	}
+}
+
+func TestPulldownRoundTripOrigins(t *testing.T) {
+	// A frames on every video frame of a cadence, and an X frame start,
+	// come back on the same timecode
+	tests := []struct{ start, end, pullin string }{
+		{"01:00:00:00", "01:00:00:10", "A"},
+		{"01:00:00:01", "01:00:00:11", "A"},
+		{"01:00:00:02", "01:00:00:12", "A"},
+		{"01:00:00:03", "01:00:00:13", "A"},
+		{"01:00:00:04", "01:00:00:14", "A"},
+		{"01:00:00:04", "01:00:00:09", "X"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.start+tt.pullin, func(t *testing.T) {
+			input := "Heading\nFIELD_DELIM\tTABS\nFPS\t29.97\n\nColumn\nName\tStart\tEnd\tPullin\n\nData\n" +
+				"A001\t" + tt.start + "\t" + tt.end + "\t" + tt.pullin + "\n"
+			timeline, err := NewDecoder(strings.NewReader(input), WithPulldown(true)).Decode()
+			if err != nil {
+				t.Fatalf("Failed to decode ALE: %v", err)
+			}
+
+			var buf bytes.Buffer
+			if err := NewEncoder(&buf, WithEncoderFPS(FilmFPS), WithEncoderPulldown(true)).Encode(timeline); err != nil {
+				t.Fatalf("Failed to encode timeline: %v", err)
+			}
+			aleFile, err := ReadALE(&buf)
+			if err != nil {
+				t.Fatalf("Failed to read encoded ALE: %v", err)
+			}
+			row := aleFile.Row(0)
+			want := map[string]string{ColumnStart: tt.start, ColumnEnd: tt.end, ColumnPullin: tt.pullin}
+			for col, value := range want {
+				if got := row.Get(col); got != value {
+					t.Errorf("%s = %q, want %q", col, got, value)
+				}
+			}
+		})
+	}
+}
+
+func TestEncoder_WithEncoderPulldownFrom24(t *testing.T) {
+	// 24 fps frame numbers are film frames, not rescaled by wall-clock time
+	input := "Heading\nFIELD_DELIM\tTABS\nFPS\t24\n\nColumn\nName\tStart\tEnd\n\nData\nA001\t01:00:00:00\t01:00:00:04\n"
+	timeline, err := NewDecoder(strings.NewReader(input)).Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode ALE: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf, WithEncoderFPS(24), WithEncoderPulldown(true)).Encode(timeline); err != nil {
+		t.Fatalf("Failed to encode timeline: %v", err)
+	}
+	aleFile, err := ReadALE(&buf)
+	if err != nil {
+		t.Fatalf("Failed to read encoded ALE: %v", err)
+	}
+	row := aleFile.Row(0)
+	want := map[string]string{ColumnStart: "01:00:00:00", ColumnEnd: "01:00:00:05", ColumnPullin: "A", ColumnPullout: "D"}
+	for col, value := range want {
+		if got := row.Get(col); got != value {
+			t.Errorf("%s = %q, want %q", col, got, value)
+		}
+	}
+}
+
+func TestDecoder_PulldownUnsupportedCadence(t *testing.T) {
+	input := strings.Replace(pulldownALE, "Pullin\tPullout", "Pullin\tPullout\tCadence", 1)
+	input = strings.Replace(input, "\tA\tD\n", "\tA\tD\t2:3:3:2\n", 1)
+
+	decoder := NewDecoder(strings.NewReader(input), WithPulldown(true))
+	timeline, err := decoder.Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode ALE: %v", err)
+	}
+
+	// The row keeps its video timecode
+	clip := timeline.FindClips(nil, false)[0]
+	if tc, _ := formatTimecode(clip.SourceRange().StartTime(), VideoFPS, false); tc != "01:00:00:00" {
+		t.Errorf("Start = %s, want 01:00:00:00 at video rate", tc)
+	}
+
+	var cellErr *CellError
+	if warnings := decoder.Warnings(); len(warnings) == 0 || !errors.As(warnings[0], &cellErr) || cellErr.Column != ColumnCadence {
+		t.Errorf("Warnings = %v, want a Cadence error", warnings)
+	}
+}
+
+func TestDecoder_PulldownProgressive(t *testing.T) {
+	input := strings.Replace(pulldownALE, "Pullin\tPullout", "Pullin\tPullout\tField Motion", 1)
+	input = strings.Replace(input, "\tA\tD\n", "\tA\tD\tProgressive\n", 1)
+	input = strings.Replace(input, "\tX\tC\n", "\tX\tC\tInterlaced\n", 1)
+
+	decoder := NewDecoder(strings.NewReader(input), WithPulldown(true))
+	timeline, err := decoder.Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode ALE: %v", err)
+	}
+
+	// The progressive row keeps its video timecode
+	clips := timeline.FindClips(nil, false)
+	if rate := clips[0].SourceRange().StartTime().Rate(); rate == FilmFPS {
+		t.Errorf("Progressive row rate = %v, want the video rate", rate)
+	}
+	if rate := clips[1].SourceRange().StartTime().Rate(); rate != FilmFPS {
+		t.Errorf("Interlaced row rate = %v, want %v", rate, FilmFPS)
+	}
+
+	var cellErr *CellError
+	if warnings := decoder.Warnings(); len(warnings) == 0 || !errors.As(warnings[0], &cellErr) || cellErr.Column != ColumnFieldMotion {
+		t.Errorf("Warnings = %v, want a Field Motion error", warnings)
+	}
+}
@@ -0,0 +1,230 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package ale
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ColumnType identifies the value type declared for an ALE column
+type ColumnType int
+
+// Column types
+const (
+	TypeString ColumnType = iota
+	TypeTimecode
+	TypeInteger
+	TypeFloat
+	TypeDate
+	TypeEnum
+	TypePath
+	TypeCDL
+)
+
+// String returns the name of the column type
+func (t ColumnType) String() string {
+	switch t {
+	case TypeTimecode:
+		return "timecode"
+	case TypeInteger:
+		return "integer"
+	case TypeFloat:
+		return "float"
+	case TypeDate:
+		return "date"
+	case TypeEnum:
+		return "enum"
+	case TypePath:
+		return "path"
+	case TypeCDL:
+		return "CDL"
+	default:
+		return "string"
+	}
+}
+
+// ColumnSpec declares the type of an ALE column
+type ColumnSpec struct {
+	Type ColumnType
+	// Values lists the allowed values of a TypeEnum column (case-insensitive)
+	Values []string
+}
+
+// Schema is a registry of column types keyed by column name
+type Schema struct {
+	columns map[string]ColumnSpec
+}
+
+// NewSchema creates an empty schema
+func NewSchema() *Schema {
+	return &Schema{
+		columns: make(map[string]ColumnSpec),
+	}
+}
+
+// DefaultSchema creates a schema declaring the standard Avid columns.
+// The returned schema may be extended with Register.
+func DefaultSchema() *Schema {
+	s := NewSchema()
+
+	for _, col := range []string{
+		ColumnStart, ColumnEnd, ColumnDuration,
+		"Mark IN", "Mark OUT", "IN-OUT",
+		"Auxiliary TC1", "Auxiliary TC2", "Auxiliary TC3", "Auxiliary TC4", "Auxiliary TC5",
+		"Sound TC", "VITC", "TC 24", "TC 25", "TC 25PD", "TC 30", "TC 30NP",
+		"Aux TC 24", "Cam TC 24", "Film TC",
+	} {
+		s.Register(col, ColumnSpec{Type: TypeTimecode})
+	}
+
+	for _, col := range []string{
+		"Frame Count Start", "Frame Count End", "Frame Count Duration",
+		"Audio Bit Depth", "Audio SR",
+		"UNC First Frame", "UNC Last Frame",
+	} {
+		s.Register(col, ColumnSpec{Type: TypeInteger})
+	}
+
+	for _, col := range []string{ColumnFPS, "CFPS", "ASC_SAT", "Pixel Aspect Ratio"} {
+		s.Register(col, ColumnSpec{Type: TypeFloat})
+	}
+
+	for _, col := range DefaultDateColumns {
+		s.Register(col, ColumnSpec{Type: TypeDate})
+	}
+
+	pulldownPhases := []string{"A", "B", "C", "D", "X"}
+	s.Register("Pullin", ColumnSpec{Type: TypeEnum, Values: pulldownPhases})
+	s.Register("Pullout", ColumnSpec{Type: TypeEnum, Values: pulldownPhases})
+	s.Register("S3D Channel", ColumnSpec{Type: TypeEnum, Values: []string{"Mono", "Left", "Right"}})
+	s.Register("S3D Leading Eye", ColumnSpec{Type: TypeEnum, Values: []string{"Left", "Right"}})
+
+	for _, col := range []string{ColumnSourceFile, "Source Path", "UNC Path", "UNC"} {
+		s.Register(col, ColumnSpec{Type: TypePath})
+	}
+
+	for _, col := range []string{"ASC_SOP", "CDL"} {
+		s.Register(col, ColumnSpec{Type: TypeCDL})
+	}
+
+	return s
+}
+
+// Register declares the type of a column, replacing any previous declaration
+func (s *Schema) Register(column string, spec ColumnSpec) {
+	s.columns[column] = spec
+}
+
+// Lookup returns the declared type of a column
+func (s *Schema) Lookup(column string) (ColumnSpec, bool) {
+	if s == nil {
+		return ColumnSpec{}, false
+	}
+	spec, ok := s.columns[column]
+	return spec, ok
+}
+
+// CellError reports a cell whose value does not match its declared column type
+type CellError struct {
+	Row    int
+	Column string
+	Value  string
+	Type   ColumnType
+	Err    error
+}
+
+// Error implements the error interface
+func (e *CellError) Error() string {
+	return fmt.Sprintf("row %d column %q: invalid %s value %q: %v", e.Row, e.Column, e.Type, e.Value, e.Err)
+}
+
+// Unwrap returns the underlying parse error
+func (e *CellError) Unwrap() error {
+	return e.Err
+}
+
+// parseCell converts a cell to the value stored for its declared type.
+// Integers and floats become int64 and float64; other types keep the
+// original string once validated.
+func parseCell(spec ColumnSpec, value string, fps float64) (interface{}, error) {
+	switch spec.Type {
+	case TypeTimecode:
+		if _, err := parseTimecode(value, fps); err != nil {
+			return nil, err
+		}
+
+	case TypeInteger:
+		return strconv.ParseInt(value, 10, 64)
+
+	case TypeFloat:
+		return strconv.ParseFloat(value, 64)
+
+	case TypeEnum:
+		for _, allowed := range spec.Values {
+			if strings.EqualFold(value, allowed) {
+				return value, nil
+			}
+		}
+		return nil, fmt.Errorf("expected one of %s", strings.Join(spec.Values, ", "))
+
+	case TypeCDL:
+		if _, err := parseASCSOP(value); err != nil {
+			return nil, err
+		}
+	}
+
+	return value, nil
+}
+
+// restoreRaw replaces typed values with the logged strings they were
+// decoded from, such as "24.00" for 24, while they still read the same
+func restoreRaw(values, raw map[string]interface{}) {
+	for col, logged := range raw {
+		s, ok := logged.(string)
+		if !ok {
+			continue
+		}
+		n, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			continue
+		}
+		switch v := values[col].(type) {
+		case float64:
+			if v == n {
+				values[col] = s
+			}
+		case int64:
+			if float64(v) == n {
+				values[col] = s
+			}
+		case int:
+			if float64(v) == n {
+				values[col] = s
+			}
+		}
+	}
+}
+
+// formatCell formats a stored value for its declared column type
+func formatCell(spec ColumnSpec, value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		// Integers come back as float64 after a JSON round trip
+		if spec.Type == TypeInteger && v == math.Trunc(v) {
+			return strconv.FormatInt(int64(v), 10)
+		}
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprintf("%v", value)
+	}
+}
@@ -0,0 +1,192 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package ale
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestParseCell(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    ColumnSpec
+		value   string
+		want    interface{}
+		wantErr bool
+	}{
+		{"timecode", ColumnSpec{Type: TypeTimecode}, "01:00:00:00", "01:00:00:00", false},
+		{"invalid timecode", ColumnSpec{Type: TypeTimecode}, "1xa", nil, true},
+		{"integer", ColumnSpec{Type: TypeInteger}, "1111", int64(1111), false},
+		{"invalid integer", ColumnSpec{Type: TypeInteger}, "12.5", nil, true},
+		{"float", ColumnSpec{Type: TypeFloat}, "23.976", 23.976, false},
+		{"invalid float", ColumnSpec{Type: TypeFloat}, "fast", nil, true},
+		{"enum", ColumnSpec{Type: TypeEnum, Values: []string{"Mono", "Left"}}, "MONO", "MONO", false},
+		{"invalid enum", ColumnSpec{Type: TypeEnum, Values: []string{"Mono", "Left"}}, "Up", nil, true},
+		{"path", ColumnSpec{Type: TypePath}, "/path/to/media.mov", "/path/to/media.mov", false},
+		{"CDL", ColumnSpec{Type: TypeCDL}, "(1 1 1)(0 0 0)(1 1 1)", "(1 1 1)(0 0 0)(1 1 1)", false},
+		{"invalid CDL", ColumnSpec{Type: TypeCDL}, "13:18:25:19", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseCell(tt.spec, tt.value, 24.0)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseCell() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("parseCell() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatCell(t *testing.T) {
+	tests := []struct {
+		name  string
+		spec  ColumnSpec
+		value interface{}
+		want  string
+	}{
+		{"string", ColumnSpec{}, "Scene1", "Scene1"},
+		{"int64", ColumnSpec{Type: TypeInteger}, int64(1111), "1111"},
+		{"integer after JSON", ColumnSpec{Type: TypeInteger}, float64(1111), "1111"},
+		{"float", ColumnSpec{Type: TypeFloat}, 23.976, "23.976"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatCell(tt.spec, tt.value); got != tt.want {
+				t.Errorf("formatCell() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecoder_WithSchema(t *testing.T) {
+	data, err := os.ReadFile("testdata/sample_cdl.ale")
+	if err != nil {
+		t.Fatalf("Failed to read test file: %v", err)
+	}
+
+	schema := DefaultSchema()
+	schema.Register("R3D.ToneMap", ColumnSpec{Type: TypeEnum, Values: []string{"Low", "Medium", "High"}})
+
+	decoder := NewDecoder(bytes.NewReader(data), WithSchema(schema))
+	timeline, err := decoder.Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode ALE: %v", err)
+	}
+
+	aleMap := timeline.FindClips(nil, false)[0].Metadata()["ALE"].(map[string]interface{})
+	if aleMap["UNC First Frame"] != int64(1111) {
+		t.Errorf("UNC First Frame = %#v, want int64(1111)", aleMap["UNC First Frame"])
+	}
+	if aleMap["R3D.ToneMap"] != "Low" {
+		t.Errorf("R3D.ToneMap = %#v, want Low", aleMap["R3D.ToneMap"])
+	}
+	if len(decoder.Warnings()) != 0 {
+		t.Errorf("Unexpected warnings: %v", decoder.Warnings())
+	}
+
+	// Encoding with the same schema writes the typed values back
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf, WithEncoderSchema(schema)).Encode(timeline); err != nil {
+		t.Fatalf("Failed to encode timeline: %v", err)
+	}
+	if !strings.Contains(buf.String(), "\t1111\t") {
+		t.Error("Output missing UNC First Frame value")
+	}
+}
+
+func TestDecoder_WithSchemaInvalidCells(t *testing.T) {
+	aleContent := `Heading
+FIELD_DELIM	TABS
+
+Column
+Name	Duration	Frame Count Start	Sound TC
+
+Data
+Clip001	100	12	01:00:00:00
+Clip002	100	twelve	not a timecode
+`
+
+	decoder := NewDecoder(strings.NewReader(aleContent), WithSchema(DefaultSchema()))
+	timeline, err := decoder.Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode ALE: %v", err)
+	}
+
+	warnings := decoder.Warnings()
+	if len(warnings) != 2 {
+		t.Fatalf("Expected 2 warnings, got %d: %v", len(warnings), warnings)
+	}
+
+	var cellErr *CellError
+	if !errors.As(warnings[0], &cellErr) {
+		t.Fatalf("Warning is not a *CellError: %v", warnings[0])
+	}
+	if cellErr.Row != 1 || cellErr.Column != "Frame Count Start" || cellErr.Type != TypeInteger {
+		t.Errorf("Unexpected cell error: %v", cellErr)
+	}
+
+	// Invalid cells keep their original string
+	aleMap := timeline.FindClips(nil, false)[1].Metadata()["ALE"].(map[string]interface{})
+	if aleMap["Frame Count Start"] != "twelve" {
+		t.Errorf("Frame Count Start = %#v, want original string", aleMap["Frame Count Start"])
+	}
+}
+
+func TestDecoder_WithSchemaKeepsLoggedSpelling(t *testing.T) {
+	aleContent := "Heading\nFIELD_DELIM\tTABS\n\nColumn\nName\tDuration\tSound TC\tCFPS\tFrame Count Start\n\nData\n" +
+		"Clip001\t100\tbad\t24.00\tnone\n" +
+		"Clip002\t100\t01:00:00:00\t48.000\t0001\n"
+
+	decoder := NewDecoder(strings.NewReader(aleContent), WithSchema(DefaultSchema()))
+	timeline, err := decoder.Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode ALE: %v", err)
+	}
+
+	// Invalid cells are reported in column order
+	var columns []string
+	for _, warning := range decoder.Warnings() {
+		var cellErr *CellError
+		if errors.As(warning, &cellErr) {
+			columns = append(columns, cellErr.Column)
+		}
+	}
+	if strings.Join(columns, ",") != "Sound TC,Frame Count Start" {
+		t.Errorf("Warning columns = %v, want Sound TC then Frame Count Start", columns)
+	}
+
+	clips := timeline.FindClips(nil, false)
+	if cfps := clips[1].Metadata()["ALE"].(map[string]interface{})["CFPS"]; cfps != 48.0 {
+		t.Errorf("CFPS = %#v, want 48.0", cfps)
+	}
+
+	// Unchanged typed values are written as logged
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf, WithEncoderSchema(DefaultSchema())).Encode(timeline); err != nil {
+		t.Fatalf("Failed to encode timeline: %v", err)
+	}
+	for _, want := range []string{"\t24.00", "\t48.000", "\t0001"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Output missing logged value %q:\n%s", want, buf.String())
+		}
+	}
+
+	// Changed values are written from the typed value
+	clips[1].Metadata()["ALE"].(map[string]interface{})["CFPS"] = 50.0
+	buf.Reset()
+	if err := NewEncoder(&buf, WithEncoderSchema(DefaultSchema())).Encode(timeline); err != nil {
+		t.Fatalf("Failed to encode timeline: %v", err)
+	}
+	if !strings.Contains(buf.String(), "\t50") || strings.Contains(buf.String(), "48.000") {
+		t.Errorf("Output did not write the changed CFPS:\n%s", buf.String())
+	}
+}
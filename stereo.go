@@ -0,0 +1,275 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package ale
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Avalanche-io/gotio"
+)
+
+// Avid stereoscopic 3D columns
+const (
+	ColumnS3DClipName   = "S3D Clip Name"
+	ColumnS3DGroupName  = "S3D Group Name"
+	ColumnS3DChannel    = "S3D Channel"
+	ColumnS3DEyeOrder   = "S3D Eye Order"
+	ColumnS3DLeadingEye = "S3D Leading Eye"
+	ColumnS3DAlignment  = "S3D Alignment"
+	ColumnS3DInversion  = "S3D Inversion"
+	ColumnS3DInversionR = "S3D InversionR"
+)
+
+// Stereo eyes
+const (
+	EyeLeft  = "left"
+	EyeRight = "right"
+)
+
+// stereoColumns maps the S3D columns to their stereo metadata keys
+var stereoColumns = []struct {
+	column string
+	key    string
+}{
+	{ColumnS3DClipName, "clip_name"},
+	{ColumnS3DGroupName, "group"},
+	{ColumnS3DChannel, "channel"},
+	{ColumnS3DEyeOrder, "eye_order"},
+	{ColumnS3DLeadingEye, "leading_eye"},
+	{ColumnS3DAlignment, "alignment"},
+	{ColumnS3DInversion, "inversion"},
+	{ColumnS3DInversionR, "inversion_right"},
+}
+
+// inversionFlags maps the flip flags read from the inversion columns to
+// the stereo metadata keys holding them
+var inversionFlags = []struct {
+	column     string
+	horizontal string
+	vertical   string
+}{
+	{ColumnS3DInversion, "flip_horizontal", "flip_vertical"},
+	{ColumnS3DInversionR, "flip_horizontal_right", "flip_vertical_right"},
+}
+
+// isStereoColumn reports whether a column is one of the S3D columns
+func isStereoColumn(column string) bool {
+	for _, c := range stereoColumns {
+		if c.column == column {
+			return true
+		}
+	}
+	return false
+}
+
+// parseEye normalizes an S3D Channel or Leading Eye value to EyeLeft or
+// EyeRight
+func parseEye(value string) (string, bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "left", "l", "left eye", "lefteye":
+		return EyeLeft, true
+	case "right", "r", "right eye", "righteye":
+		return EyeRight, true
+	}
+	return "", false
+}
+
+// parseInversion reads an S3D Inversion or S3D InversionR value: None,
+// Horizontal, Vertical or Both. Other values are not read.
+func parseInversion(value string) (horizontal, vertical, ok bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "none":
+		return false, false, true
+	case "horizontal":
+		return true, false, true
+	case "vertical":
+		return false, true, true
+	case "both":
+		return true, true, true
+	}
+	return false, false, false
+}
+
+// formatInversion returns the S3D Inversion value of a pair of flip flags
+func formatInversion(horizontal, vertical bool) string {
+	switch {
+	case horizontal && vertical:
+		return "Both"
+	case horizontal:
+		return "Horizontal"
+	case vertical:
+		return "Vertical"
+	}
+	return "None"
+}
+
+// stereoMetadata collects a row's S3D columns, or returns nil if it has
+// none. The raw values are kept for the encoder alongside the normalized
+// eye and the flip flags read from the inversion columns.
+func stereoMetadata(row Row) map[string]interface{} {
+	stereo := make(map[string]interface{})
+	for _, c := range stereoColumns {
+		if value := row.Get(c.column); value != "" {
+			stereo[c.key] = value
+		}
+	}
+	if len(stereo) == 0 {
+		return nil
+	}
+
+	if eye, ok := parseEye(row.Get(ColumnS3DChannel)); ok {
+		stereo["eye"] = eye
+	}
+	for _, f := range inversionFlags {
+		if horizontal, vertical, ok := parseInversion(row.Get(f.column)); ok {
+			stereo[f.horizontal] = horizontal
+			stereo[f.vertical] = vertical
+		}
+	}
+	return stereo
+}
+
+// pairStereo pairs left- and right-eye clips that share an S3D Group Name
+// (or S3D Clip Name) into Stacks. The first clip of each pair stands in
+// for the Stack when clips are placed; the second is dropped from the
+// returned clips and rows.
+func (d *Decoder) pairStereo(clips []*gotio.Clip, rows []Row) ([]*gotio.Clip, []Row, error) {
+	type pair struct {
+		first int
+		eyes  map[string]int
+	}
+
+	pairs := make(map[string]*pair)
+	var order []string
+	for i, clip := range clips {
+		stereo := asMap(structured(clip.Metadata(), d.metadataKey, stereoField))
+		eye, _ := stereo["eye"].(string)
+		if eye == "" {
+			continue
+		}
+		group, _ := stereo["group"].(string)
+		if group == "" {
+			group, _ = stereo["clip_name"].(string)
+		}
+		if group == "" {
+			continue
+		}
+
+		p, ok := pairs[group]
+		if !ok {
+			p = &pair{first: i, eyes: make(map[string]int)}
+			pairs[group] = p
+			order = append(order, group)
+		}
+		if _, dup := p.eyes[eye]; !dup {
+			p.eyes[eye] = i
+		}
+	}
+
+	d.stereoStacks = make(map[*gotio.Clip]*gotio.Stack)
+	dropped := make(map[int]bool)
+	for _, group := range order {
+		p := pairs[group]
+		left, hasLeft := p.eyes[EyeLeft]
+		right, hasRight := p.eyes[EyeRight]
+		if !hasLeft || !hasRight {
+			continue
+		}
+
+		stack, err := d.stereoStack(group, clips[left], clips[right])
+		if err != nil {
+			return nil, nil, err
+		}
+		d.stereoStacks[clips[p.first]] = stack
+
+		second := right
+		if p.first == right {
+			second = left
+		}
+		dropped[second] = true
+	}
+
+	var outClips []*gotio.Clip
+	var outRows []Row
+	for i, clip := range clips {
+		if dropped[i] {
+			continue
+		}
+		outClips = append(outClips, clip)
+		outRows = append(outRows, rows[i])
+	}
+	return outClips, outRows, nil
+}
+
+// stereoStack builds a Stack holding the left and right eye of a pair,
+// named after the S3D Clip Name
+func (d *Decoder) stereoStack(group string, left, right *gotio.Clip) (*gotio.Stack, error) {
+	eye := asMap(structured(left.Metadata(), d.metadataKey, stereoField))
+
+	name, _ := eye["clip_name"].(string)
+	if name == "" {
+		name = group
+	}
+	stereo := map[string]interface{}{"group": group}
+	for _, k := range []string{"clip_name", "eye_order", "leading_eye"} {
+		if value, ok := eye[k]; ok {
+			stereo[k] = value
+		}
+	}
+
+	stack := gotio.NewStack(name, nil, gotio.AnyDictionary{d.metadataKey: map[string]interface{}{stereoField: stereo}}, nil, nil, nil)
+	for _, clip := range []*gotio.Clip{left, right} {
+		if err := stack.AppendChild(clip); err != nil {
+			return nil, fmt.Errorf("failed to append eye to stereo pair: %w", err)
+		}
+	}
+	return stack, nil
+}
+
+// item returns what is placed for a clip: its stereo pair Stack if it
+// stands in for one, otherwise the clip itself
+func (d *Decoder) item(clip *gotio.Clip) gotio.Composable {
+	if stack, ok := d.stereoStacks[clip]; ok {
+		return stack
+	}
+	return clip
+}
+
+// stereoValue returns the value of an S3D column from a clip's stereo
+// metadata, deriving the channel from the eye and the inversion columns
+// from the flip flags when they were not decoded
+func (e *Encoder) stereoValue(metadata gotio.AnyDictionary, column string) (string, bool) {
+	stereo := asMap(structured(metadata, e.metadataKey, stereoField))
+	if stereo == nil {
+		return "", false
+	}
+	for _, c := range stereoColumns {
+		if c.column != column {
+			continue
+		}
+		if value, ok := stereo[c.key].(string); ok {
+			return value, true
+		}
+	}
+	if column == ColumnS3DChannel {
+		switch stereo["eye"] {
+		case EyeLeft:
+			return "Left", true
+		case EyeRight:
+			return "Right", true
+		}
+	}
+	for _, f := range inversionFlags {
+		if f.column != column {
+			continue
+		}
+		horizontal, hasHorizontal := stereo[f.horizontal].(bool)
+		vertical, hasVertical := stereo[f.vertical].(bool)
+		if hasHorizontal || hasVertical {
+			return formatInversion(horizontal, vertical), true
+		}
+	}
+	return "", false
+}
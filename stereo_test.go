@@ -0,0 +1,185 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package ale
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Avalanche-io/gotio"
+)
+
+const stereoALE = `Heading
+FIELD_DELIM	TABS
+FPS	24
+
+Column
+Name	Start	End	S3D Clip Name	S3D Group Name	S3D Channel	S3D Eye Order	S3D Leading Eye	S3D Alignment	S3D Inversion	S3D InversionR
+
+Data
+A001_R	01:00:00:00	01:00:10:00	SHOT_010	G1	Right	Left/Right	Left	NONE	None	Horizontal
+A001_L	01:00:00:00	01:00:10:00	SHOT_010	G1	Left	Left/Right	Left	NONE	Vertical	None
+B001	01:00:10:00	01:00:20:00
+`
+
+func TestDecoder_WithStereoPairs(t *testing.T) {
+	timeline, err := NewDecoder(strings.NewReader(stereoALE), WithStereoPairs(true)).Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode ALE: %v", err)
+	}
+
+	track := timeline.Tracks().Children()[0].(*gotio.Track)
+	children := track.Children()
+	if len(children) != 2 {
+		t.Fatalf("Expected 2 track items, got %d", len(children))
+	}
+
+	stack, ok := children[0].(*gotio.Stack)
+	if !ok {
+		t.Fatalf("First item is %T, want *gotio.Stack", children[0])
+	}
+	if stack.Name() != "SHOT_010" {
+		t.Errorf("Stack name = %q, want SHOT_010", stack.Name())
+	}
+	if pair := asMap(asMap(stack.Metadata()["ALE"])["_stereo"]); pair["leading_eye"] != "Left" {
+		t.Errorf("Stack leading_eye = %v, want Left", pair["leading_eye"])
+	}
+
+	// Left eye first, whatever the row order
+	eyes := stack.Children()
+	if len(eyes) != 2 {
+		t.Fatalf("Expected 2 eyes, got %d", len(eyes))
+	}
+	left, right := eyes[0].(*gotio.Clip), eyes[1].(*gotio.Clip)
+	if left.Name() != "A001_L" || right.Name() != "A001_R" {
+		t.Errorf("Eyes = %s, %s, want A001_L, A001_R", left.Name(), right.Name())
+	}
+
+	stereo := asMap(asMap(right.Metadata()["ALE"])["_stereo"])
+	if stereo["eye"] != EyeRight {
+		t.Errorf("Right eye = %v, want %s", stereo["eye"], EyeRight)
+	}
+	if stereo["flip_horizontal"] != false || stereo["flip_vertical"] != false {
+		t.Errorf("Right row flips = %v, %v, want false, false", stereo["flip_horizontal"], stereo["flip_vertical"])
+	}
+	if stereo["flip_horizontal_right"] != true || stereo["flip_vertical_right"] != false {
+		t.Errorf("Right row right-eye flips = %v, %v, want true, false", stereo["flip_horizontal_right"], stereo["flip_vertical_right"])
+	}
+	if _, ok := asMap(right.Metadata()["ALE"])[ColumnS3DChannel]; ok {
+		t.Error("S3D columns should only be stored as stereo metadata")
+	}
+
+	if mono, ok := children[1].(*gotio.Clip); !ok || mono.Name() != "B001" {
+		t.Errorf("Second item = %v, want clip B001", children[1])
+	}
+
+	// The pair is written back as two annotated rows
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).Encode(timeline); err != nil {
+		t.Fatalf("Failed to encode timeline: %v", err)
+	}
+
+	roundTrip, err := NewDecoder(bytes.NewReader(buf.Bytes())).Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode encoded timeline: %v", err)
+	}
+	want := map[string]map[string]string{
+		"A001_L": {ColumnS3DChannel: "Left", ColumnS3DInversion: "Vertical", ColumnS3DInversionR: "None", ColumnS3DGroupName: "G1"},
+		"A001_R": {ColumnS3DChannel: "Right", ColumnS3DInversion: "None", ColumnS3DInversionR: "Horizontal", ColumnS3DClipName: "SHOT_010"},
+	}
+	for _, clip := range roundTrip.FindClips(nil, false) {
+		columns := asMap(clip.Metadata()["ALE"])
+		for col, value := range want[clip.Name()] {
+			if columns[col] != value {
+				t.Errorf("Clip %q %s = %v, want %q", clip.Name(), col, columns[col], value)
+			}
+		}
+	}
+}
+
+func TestDecoder_StereoUnpairedRows(t *testing.T) {
+	// Without a matching eye the row stays a plain clip
+	input := strings.Replace(stereoALE, "A001_L\t01:00:00:00\t01:00:10:00\tSHOT_010\tG1\tLeft", "A001_L\t01:00:00:00\t01:00:10:00\tSHOT_010\tG2\tLeft", 1)
+	timeline, err := NewDecoder(strings.NewReader(input), WithStereoPairs(true)).Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode ALE: %v", err)
+	}
+
+	track := timeline.Tracks().Children()[0].(*gotio.Track)
+	if got := describeTrack(track); got != "A001_R,A001_L,B001" {
+		t.Errorf("Track = %s, want A001_R,A001_L,B001", got)
+	}
+}
+
+func TestDecoder_StereoSample(t *testing.T) {
+	// sample.ale logs mono clips with an alignment and empty inversions
+	data, err := os.ReadFile("testdata/sample.ale")
+	if err != nil {
+		t.Fatalf("Failed to read test file: %v", err)
+	}
+	timeline, err := NewDecoder(bytes.NewReader(data), WithStereoPairs(true)).Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode ALE: %v", err)
+	}
+
+	clip := timeline.FindClips(nil, false)[0]
+	stereo := asMap(structured(clip.Metadata(), DefaultMetadataKey, stereoField))
+	if stereo["channel"] != "MONO" || stereo["alignment"] != "NONE" {
+		t.Errorf("Stereo = %v, want channel MONO and alignment NONE", stereo)
+	}
+	for _, key := range []string{"inversion", "inversion_right", "flip_horizontal", "flip_vertical"} {
+		if _, ok := stereo[key]; ok {
+			t.Errorf("Stereo has %s from an empty inversion column", key)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).Encode(timeline); err != nil {
+		t.Fatalf("Failed to encode timeline: %v", err)
+	}
+	aleFile, err := NewDecoder(bytes.NewReader(buf.Bytes())).parseALE(t.Context())
+	if err != nil {
+		t.Fatalf("Failed to parse encoded ALE: %v", err)
+	}
+	row := aleFile.Row(0)
+	if row.Get(ColumnS3DChannel) != "MONO" || row.Get(ColumnS3DAlignment) != "NONE" {
+		t.Errorf("S3D Channel, Alignment = %q, %q, want MONO, NONE", row.Get(ColumnS3DChannel), row.Get(ColumnS3DAlignment))
+	}
+	for _, col := range []string{ColumnS3DInversion, ColumnS3DInversionR} {
+		if _, ok := aleFile.ColumnIndex(col); ok {
+			t.Errorf("Empty %s column should not be written", col)
+		}
+	}
+}
+
+func TestParseInversion(t *testing.T) {
+	tests := []struct {
+		value      string
+		horizontal bool
+		vertical   bool
+		ok         bool
+	}{
+		{"None", false, false, true},
+		{"NONE", false, false, true},
+		{"Horizontal", true, false, true},
+		{"Vertical", false, true, true},
+		{"Both", true, true, true},
+		{"Flip H", false, false, false},
+		{"", false, false, false},
+	}
+
+	for _, tt := range tests {
+		horizontal, vertical, ok := parseInversion(tt.value)
+		if horizontal != tt.horizontal || vertical != tt.vertical || ok != tt.ok {
+			t.Errorf("parseInversion(%q) = %v, %v, %v, want %v, %v, %v", tt.value, horizontal, vertical, ok, tt.horizontal, tt.vertical, tt.ok)
+		}
+		if tt.ok && tt.value != "NONE" {
+			if got := formatInversion(horizontal, vertical); got != tt.value {
+				t.Errorf("formatInversion(%v, %v) = %q, want %q", horizontal, vertical, got, tt.value)
+			}
+		}
+	}
+}
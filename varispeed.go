@@ -0,0 +1,91 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package ale
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/Avalanche-io/gotio"
+)
+
+// Varispeed columns
+const (
+	// ColumnCFPS is the camera capture rate
+	ColumnCFPS = "CFPS"
+	// ColumnSpeed is the playback speed as a percentage of capture speed
+	ColumnSpeed = "Speed"
+)
+
+// varispeedTolerance is how far CFPS may differ from FPS and still be
+// treated as normal speed, absorbing rounding such as 23.98 vs 23.976
+const varispeedTolerance = 0.005
+
+// varispeedEffectName is the effect name of decoded varispeed time warps
+const varispeedEffectName = "Varispeed"
+
+// varispeedWarp returns a LinearTimeWarp for a row shot off-speed, with a
+// time scalar of FPS / CFPS: a clip shot at 48 fps for a 24 fps project
+// plays at 0.5. A Speed percentage is used when there is no CFPS.
+func (d *Decoder) varispeedWarp(row Row, index int) (*gotio.LinearTimeWarp, error) {
+	fps := d.fps
+	if value := row.Get(ColumnFPS); value != "" {
+		if rowFPS, err := parseFPS(value); err == nil {
+			fps = rowFPS
+		}
+	}
+
+	var scalar float64
+	if value := row.Get(ColumnCFPS); value != "" {
+		cfps, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || cfps <= 0 {
+			return nil, &CellError{Row: index, Column: ColumnCFPS, Value: value, Type: TypeFloat, Err: fmt.Errorf("invalid capture rate")}
+		}
+		if math.Abs(cfps-fps) <= varispeedTolerance*fps {
+			return nil, nil
+		}
+		scalar = fps / cfps
+	} else if value := row.Get(ColumnSpeed); value != "" {
+		speed, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(value), "%"), 64)
+		if err != nil || speed <= 0 {
+			return nil, &CellError{Row: index, Column: ColumnSpeed, Value: value, Type: TypeFloat, Err: fmt.Errorf("invalid speed")}
+		}
+		scalar = speed / 100
+		if math.Abs(scalar-1) <= varispeedTolerance {
+			return nil, nil
+		}
+	} else {
+		return nil, nil
+	}
+
+	return gotio.NewLinearTimeWarp(varispeedEffectName, "LinearTimeWarp", scalar, nil), nil
+}
+
+// clipTimeScalar returns the combined time scalar of a clip's linear time
+// warps, or false if it has none or they freeze or reverse the clip
+func clipTimeScalar(clip *gotio.Clip) (float64, bool) {
+	scalar, found := 1.0, false
+	for _, effect := range clip.Effects() {
+		if warp, ok := effect.(*gotio.LinearTimeWarp); ok {
+			scalar *= warp.TimeScalar()
+			found = true
+		}
+	}
+	return scalar, found && scalar > 0
+}
+
+// sameRate reports whether a logged CFPS or Speed value reads as rate,
+// so "48" or "200%" is kept instead of being rewritten as "48.00"
+func sameRate(value string, rate float64) bool {
+	logged, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(value), "%"), 64)
+	return err == nil && math.Abs(logged-rate) <= varispeedTolerance*rate
+}
+
+// formatRate formats a frame rate or percentage with two decimals, as
+// Avid writes FPS and CFPS
+func formatRate(value float64) string {
+	return strconv.FormatFloat(value, 'f', 2, 64)
+}
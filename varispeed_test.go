@@ -0,0 +1,149 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package ale
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Avalanche-io/gotio"
+)
+
+const varispeedALE = `Heading
+FIELD_DELIM	TABS
+FPS	24
+
+Column
+Name	Start	End	CFPS	Speed
+
+Data
+A001	01:00:00:00	01:00:01:00	48.00	
+A002	01:00:01:00	01:00:02:00	24.00	
+A003	01:00:02:00	01:00:03:00		200
+A004	01:00:03:00	01:00:04:00	fast	
+`
+
+func TestDecoder_WithVarispeed(t *testing.T) {
+	decoder := NewDecoder(strings.NewReader(varispeedALE), WithVarispeed(true))
+	timeline, err := decoder.Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode ALE: %v", err)
+	}
+
+	clips := timeline.FindClips(nil, false)
+	if len(clips) != 4 {
+		t.Fatalf("Expected 4 clips, got %d", len(clips))
+	}
+
+	want := []struct {
+		scalar float64
+		warped bool
+	}{
+		{0.5, true},
+		{1, false},
+		{2, true},
+		{1, false},
+	}
+	for i, clip := range clips {
+		scalar, warped := clipTimeScalar(clip)
+		if warped != want[i].warped || scalar != want[i].scalar {
+			t.Errorf("Clip %d time scalar = %v (%v), want %v (%v)", i, scalar, warped, want[i].scalar, want[i].warped)
+		}
+	}
+
+	if effects := clips[0].Effects(); len(effects) != 1 || effects[0].Name() != varispeedEffectName {
+		t.Errorf("Clip 0 effects = %v, want one %s time warp", effects, varispeedEffectName)
+	}
+
+	// A004 has an unreadable capture rate
+	warnings := decoder.Warnings()
+	if len(warnings) != 1 {
+		t.Fatalf("Expected 1 warning, got %v", warnings)
+	}
+	var cellErr *CellError
+	if !errors.As(warnings[0], &cellErr) || cellErr.Column != ColumnCFPS || cellErr.Row != 3 {
+		t.Errorf("Warning = %v, want a CFPS error on row 3", warnings[0])
+	}
+}
+
+func TestDecoder_VarispeedDisabled(t *testing.T) {
+	timeline, err := NewDecoder(strings.NewReader(varispeedALE)).Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode ALE: %v", err)
+	}
+	for i, clip := range timeline.FindClips(nil, false) {
+		if len(clip.Effects()) != 0 {
+			t.Errorf("Clip %d has effects without WithVarispeed", i)
+		}
+	}
+}
+
+func TestEncoder_LinearTimeWarp(t *testing.T) {
+	timeline, err := NewDecoder(strings.NewReader(varispeedALE), WithVarispeed(true)).Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode ALE: %v", err)
+	}
+
+	// A time warp added after decoding is written too
+	clip := timeline.FindClips(nil, false)[1]
+	clip.SetEffects([]gotio.Effect{gotio.NewLinearTimeWarp("Slow", "LinearTimeWarp", 0.25, nil)})
+
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).Encode(timeline); err != nil {
+		t.Fatalf("Failed to encode timeline: %v", err)
+	}
+
+	aleFile, err := NewDecoder(bytes.NewReader(buf.Bytes())).parseALE(t.Context())
+	if err != nil {
+		t.Fatalf("Failed to parse encoded ALE: %v", err)
+	}
+	want := []map[string]string{
+		{ColumnCFPS: "48.00", ColumnSpeed: "50.00"},
+		{ColumnCFPS: "96.00", ColumnSpeed: "25.00"},
+		{ColumnCFPS: "12.00", ColumnSpeed: "200"},
+		{ColumnCFPS: "fast"},
+	}
+	for i, row := range aleFile.RowMaps() {
+		for col, value := range want[i] {
+			if row[col] != value {
+				t.Errorf("Row %d %s = %q, want %q", i, col, row[col], value)
+			}
+		}
+	}
+}
+
+func TestEncoder_LinearTimeWarpColumns(t *testing.T) {
+	// A logged CFPS reading as the warp's rate is kept, Speed is not added
+	// and a freeze frame has no capture rate
+	input := "Heading\nFIELD_DELIM\tTABS\nFPS\t24\n\nColumn\nName\tStart\tEnd\tCFPS\n\nData\n" +
+		"A001\t01:00:00:00\t01:00:01:00\t48\n" +
+		"A002\t01:00:01:00\t01:00:02:00\t\n"
+	timeline, err := NewDecoder(strings.NewReader(input), WithVarispeed(true)).Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode ALE: %v", err)
+	}
+	clips := timeline.FindClips(nil, false)
+	clips[1].SetEffects([]gotio.Effect{gotio.NewLinearTimeWarp("Freeze", "LinearTimeWarp", 0, nil)})
+
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf).Encode(timeline); err != nil {
+		t.Fatalf("Failed to encode timeline: %v", err)
+	}
+	if strings.Contains(buf.String(), ColumnSpeed) {
+		t.Error("Output has a Speed column that was not logged")
+	}
+
+	aleFile, err := NewDecoder(bytes.NewReader(buf.Bytes())).parseALE(t.Context())
+	if err != nil {
+		t.Fatalf("Failed to parse encoded ALE: %v", err)
+	}
+	if got := aleFile.Row(0).Get(ColumnCFPS); got != "48" {
+		t.Errorf("Row 0 CFPS = %q, want the logged 48", got)
+	}
+	if got := aleFile.Row(1).Get(ColumnCFPS); got != "" {
+		t.Errorf("Row 1 CFPS = %q, want none for a freeze frame", got)
+	}
+}
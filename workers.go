@@ -0,0 +1,110 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package ale
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/Avalanche-io/gotio"
+)
+
+// rowResult is the outcome of converting one row
+type rowResult struct {
+	index    int
+	clip     *gotio.Clip
+	warnings []error
+	err      error
+}
+
+// convertRowsParallel converts rows to clips on a bounded pool of workers.
+// At most two rows per worker are in flight or waiting to be collected,
+// and results are collected in file order. Once a row fails or ctx is done no further rows
+// are handed out, and the first failure in row order is returned.
+func (d *Decoder) convertRowsParallel(ctx context.Context, aleFile *ALEFile) ([]*gotio.Clip, []Row, error) {
+	stop, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	window := 2 * d.workers
+	slots := make(chan struct{}, window)
+	indices := make(chan int)
+	results := make(chan rowResult, window)
+
+	var wg sync.WaitGroup
+	for range min(d.workers, len(aleFile.Rows)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				clip, warnings, err := d.rowToClip(d.row(aleFile, i), i)
+				if err != nil {
+					cancel()
+				}
+				results <- rowResult{index: i, clip: clip, warnings: warnings, err: err}
+			}
+		}()
+	}
+
+	// Every dispatched row is converted in full, so a row error is never
+	// masked by the cancellation it triggers
+	go func() {
+		defer close(indices)
+		for i := range aleFile.Rows {
+			select {
+			case slots <- struct{}{}:
+			case <-stop.Done():
+				return
+			}
+			select {
+			case indices <- i:
+			case <-stop.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var clips []*gotio.Clip
+	var rows []Row
+	var firstErr error
+	pending := make(map[int]rowResult, window)
+	next := 0
+	for result := range results {
+		pending[result.index] = result
+		for {
+			result, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			<-slots
+
+			if firstErr == nil {
+				if result.err != nil {
+					firstErr = fmt.Errorf("failed to convert row %d to clip: %w", next, result.err)
+				} else {
+					d.warnings = append(d.warnings, result.warnings...)
+					if result.clip != nil {
+						clips = append(clips, result.clip)
+						rows = append(rows, d.row(aleFile, next))
+					}
+				}
+			}
+			next++
+		}
+	}
+
+	if firstErr != nil {
+		return nil, nil, firstErr
+	}
+	if next < len(aleFile.Rows) {
+		return nil, nil, fmt.Errorf("decode cancelled at row %d: %w", next, ctx.Err())
+	}
+
+	return clips, rows, nil
+}
@@ -0,0 +1,171 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Contributors to the OpenTimelineIO project
+
+package ale
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"runtime/metrics"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Avalanche-io/gotio/opentime"
+)
+
+// generateALE builds an ALE with the given number of rows, each with
+// timecodes, a camera, a CDL and a date
+func generateALE(rows int) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("Heading\nFIELD_DELIM\tTABS\nFPS\t24\n\nColumn\n")
+	buf.WriteString("Name\tTracks\tStart\tEnd\tCamera\tScene\tTake\tShoot Date\tASC_SOP\tASC_SAT\tSource File\n\nData\n")
+	for i := range rows {
+		start := opentime.NewRationalTime(float64(86400+i*48), 24)
+		end := opentime.NewRationalTime(float64(86400+i*48+48), 24)
+		startTC, _ := formatTimecode(start, 24, false)
+		endTC, _ := formatTimecode(end, 24, false)
+		fmt.Fprintf(&buf, "A%03dC%04d\tV\t%s\t%s\t%c\t%d\t%d\t2019050%d\t(1.1 1.0 0.9)(0.01 0.0 -0.01)(1.0 1.0 1.0)\t0.9\t/media/A%03dC%04d.mov\n",
+			i/1000, i%1000, startTC, endTC, 'A'+i%3, i/100, i%10, 1+i%5, i/1000, i%1000)
+	}
+	return buf.Bytes()
+}
+
+// clipNames lists the clip names of a timeline in track order
+func clipNames(t *testing.T, data []byte, opts ...DecoderOption) ([]string, []error) {
+	t.Helper()
+	decoder := NewDecoder(bytes.NewReader(data), opts...)
+	timeline, err := decoder.Decode()
+	if err != nil {
+		t.Fatalf("Failed to decode ALE: %v", err)
+	}
+	var names []string
+	for _, clip := range timeline.FindClips(nil, false) {
+		names = append(names, clip.Name())
+	}
+	return names, decoder.Warnings()
+}
+
+func TestDecoder_WithWorkersPreservesOrder(t *testing.T) {
+	data := generateALE(2000)
+
+	// Break every 7th date so warnings can be compared too
+	lines := strings.Split(string(data), "\n")
+	for i := 8; i < len(lines); i += 7 {
+		lines[i] = strings.Replace(lines[i], "\t2019050", "\tnot a date ", 1)
+	}
+	data = []byte(strings.Join(lines, "\n"))
+
+	schema := NewSchema()
+	schema.Register(ColumnShootDate, ColumnSpec{Type: TypeDate})
+
+	wantNames, wantWarnings := clipNames(t, data, WithSchema(schema))
+	gotNames, gotWarnings := clipNames(t, data, WithSchema(schema), WithWorkers(8))
+
+	if strings.Join(gotNames, ",") != strings.Join(wantNames, ",") {
+		t.Error("Parallel decode changed clip order")
+	}
+	if len(wantWarnings) == 0 {
+		t.Fatal("Expected warnings for invalid dates")
+	}
+	if len(gotWarnings) != len(wantWarnings) {
+		t.Fatalf("Parallel decode returned %d warnings, want %d", len(gotWarnings), len(wantWarnings))
+	}
+	for i := range wantWarnings {
+		if gotWarnings[i].Error() != wantWarnings[i].Error() {
+			t.Errorf("Warning %d = %v, want %v", i, gotWarnings[i], wantWarnings[i])
+		}
+	}
+}
+
+func TestDecoder_WithWorkersReportsFirstError(t *testing.T) {
+	lines := strings.Split(string(generateALE(500)), "\n")
+	for _, i := range []int{300, 120} {
+		lines[i] = strings.Replace(lines[i], "\tV\t", "\tV\tbad\t", 1)
+	}
+	data := []byte(strings.Join(lines, "\n"))
+
+	_, err := NewDecoder(bytes.NewReader(data), WithWorkers(4)).Decode()
+	if err == nil {
+		t.Fatal("Expected error for invalid timecode")
+	}
+	// Data rows start after the 8 heading and column lines
+	if !strings.Contains(err.Error(), "row 112 ") {
+		t.Errorf("Error = %v, want the first bad row (112)", err)
+	}
+}
+
+func TestDecoder_WithWorkersCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	decoder := NewDecoder(bytes.NewReader(generateALE(100)), WithWorkers(4))
+
+	aleFile, err := decoder.read(ctx)
+	if err != nil {
+		t.Fatalf("Failed to parse ALE: %v", err)
+	}
+	cancel()
+
+	if _, err := decoder.aleToTimeline(ctx, aleFile); !errors.Is(err, context.Canceled) {
+		t.Errorf("Decode error = %v, want context.Canceled", err)
+	}
+}
+
+var largeALE = sync.OnceValue(func() []byte {
+	return generateALE(500_000)
+})
+
+// peakHeap calls fn while sampling the live heap, and returns the highest
+// sample in bytes
+func peakHeap(fn func()) uint64 {
+	sample := []metrics.Sample{{Name: "/memory/classes/heap/objects:bytes"}}
+	var peak atomic.Uint64
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(time.Millisecond)
+		defer ticker.Stop()
+		for {
+			metrics.Read(sample)
+			if v := sample[0].Value.Uint64(); v > peak.Load() {
+				peak.Store(v)
+			}
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	fn()
+	close(done)
+	wg.Wait()
+	return peak.Load()
+}
+
+func BenchmarkDecode500k(b *testing.B) {
+	data := largeALE()
+
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(data)))
+			var peak uint64
+			for b.Loop() {
+				runtime.GC()
+				peak = max(peak, peakHeap(func() {
+					if _, err := NewDecoder(bytes.NewReader(data), WithWorkers(workers)).Decode(); err != nil {
+						b.Fatal(err)
+					}
+				}))
+			}
+			b.ReportMetric(float64(peak)/(1<<20), "peak-MiB")
+		})
+	}
+}